@@ -0,0 +1,44 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// adminScheme names the security scheme of the admin endpoints in the
+// OpenAPI document.
+const adminScheme = "adminToken"
+
+/*
+admin marks the operations of e as restricted to administrators and only
+serves the requests sending the admin token of the Handler as a bearer
+token in the Authorization header. The others are answered with 401.
+*/
+func (h *Handler) admin(e endpoint) endpoint {
+	operations := make([]operation, 0, len(e.operations))
+	for _, o := range e.operations {
+		o.admin = true
+		operations = append(operations, o)
+	}
+
+	next := e.handler
+	return endpoint{e.path, func(w http.ResponseWriter, r *http.Request) {
+		if !h.isAdmin(r) {
+			h.logger.WarnContext(r.Context(), fmt.Sprintf("refused %s %s without the admin token", r.Method, r.URL.Path))
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("the admin token is missing or wrong"))
+			return
+		}
+		next(w, r)
+	}, operations}
+}
+
+// isAdmin reports whether r carries the admin token, compared in constant
+// time. No request does when the Handler has none.
+func (h *Handler) isAdmin(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && h.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) == 1
+}
@@ -0,0 +1,189 @@
+/*
+Package api serves the Cars API over HTTP. A Handler holds everything the
+endpoints depend on, so several APIs can run in one process and services
+can embed the API next to their own routes.
+*/
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/exchange"
+	"github.com/YoungOak/GoAPI/internal/idempotency"
+	"github.com/YoungOak/GoAPI/internal/openapi"
+	"github.com/YoungOak/GoAPI/internal/reservation"
+	"github.com/YoungOak/GoAPI/server"
+	"github.com/YoungOak/GoAPI/snapshot"
+	"github.com/YoungOak/GoAPI/vocab"
+)
+
+const (
+	defaultIdempotencyTTL  = 24 * time.Hour
+	defaultReservationHold = 48 * time.Hour
+)
+
+// Handler serves the endpoints of the API from a data.Manager.
+type Handler struct {
+	cars            data.Manager
+	logger          *slog.Logger
+	idempotency     *idempotency.Store
+	rates           *exchange.Rates
+	vocabulary      *vocab.Registry
+	reservations    *reservation.Service
+	reservationHold time.Duration
+	snapshots       *snapshot.Store
+	adminToken      string
+	maxBodySize     int64
+	// version is the version of the API served, the copies made by
+	// Register serving the others.
+	version *apiVersion
+
+	validateRequests  bool
+	validateResponses bool
+}
+
+type Option func(*Handler)
+
+// WithExchangeRates lets GET /cars convert prices with rates.
+func WithExchangeRates(rates *exchange.Rates) Option {
+	return func(h *Handler) {
+		h.rates = rates
+	}
+}
+
+// WithVocabulary serves the terms of registry, which should be the one
+// the manager validates records with.
+func WithVocabulary(registry *vocab.Registry) Option {
+	return func(h *Handler) {
+		h.vocabulary = registry
+	}
+}
+
+// WithSnapshots enables the admin snapshot endpoints, saving to store.
+// They are only served with WithAdminToken.
+func WithSnapshots(store *snapshot.Store) Option {
+	return func(h *Handler) {
+		h.snapshots = store
+	}
+}
+
+// WithAdminToken sets the bearer token the admin endpoints require, they
+// are not served without one.
+func WithAdminToken(token string) Option {
+	return func(h *Handler) {
+		h.adminToken = token
+	}
+}
+
+// WithReservationHold sets how long a reservation lasts when the request
+// does not say.
+func WithReservationHold(d time.Duration) Option {
+	return func(h *Handler) {
+		h.reservationHold = d
+	}
+}
+
+// WithIdempotencyTTL sets how long responses are kept for replay by
+// Idempotency-Key.
+func WithIdempotencyTTL(ttl time.Duration) Option {
+	return func(h *Handler) {
+		h.idempotency = idempotency.NewStore(ttl)
+	}
+}
+
+// WithMaxBodySize sets the largest request body accepted in bytes, larger
+// ones are refused with 413.
+func WithMaxBodySize(n int64) Option {
+	return func(h *Handler) {
+		h.maxBodySize = n
+	}
+}
+
+// WithRequestValidation checks the parameters and JSON bodies of requests
+// against the OpenAPI document of the API before they reach the handlers.
+func WithRequestValidation() Option {
+	return func(h *Handler) {
+		h.validateRequests = true
+	}
+}
+
+/*
+WithResponseValidation checks requests like WithRequestValidation, and
+responses too: a response the document does not describe is replaced by
+a 500 naming the violation. It buffers every response and is meant for
+tests.
+*/
+func WithResponseValidation() Option {
+	return func(h *Handler) {
+		h.validateRequests = true
+		h.validateResponses = true
+	}
+}
+
+// NewHandler returns a Handler serving the cars of cars, logging to logger
+// and timing reservations with clk.
+func NewHandler(cars data.Manager, logger *slog.Logger, clk clock.Clock, opts ...Option) *Handler {
+	h := &Handler{
+		cars:            cars,
+		logger:          logger,
+		idempotency:     idempotency.NewStore(defaultIdempotencyTTL),
+		vocabulary:      vocab.NewRegistry(),
+		reservations:    reservation.NewService(cars, clk),
+		reservationHold: defaultReservationHold,
+		maxBodySize:     defaultMaxBodySize,
+		version:         versions[0],
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+/*
+Register adds the routes of the API to router: the routes of each version
+under its name, such as /v2/cars, and the same routes without prefix,
+serving the version the Accept header asks for, v1 by default. The
+snapshot endpoints are only added when the Handler has a snapshot store
+and an admin token.
+*/
+func (h *Handler) Register(router server.Router) {
+	var paths []string
+	byPath := make(map[string]map[*apiVersion]http.HandlerFunc)
+	for _, v := range versions {
+		versioned := h.withVersion(v)
+		group := server.Group(router, "/"+v.name)
+
+		var document openapi.Document
+		if h.validateRequests {
+			document = versioned.OpenAPI()
+		}
+		for _, e := range versioned.endpoints() {
+			handler := e.handler
+			if h.validateRequests {
+				handler = versioned.validate(&document, document.Paths[e.path], handler)
+			}
+			group.AddHandler(e.path, handler)
+
+			if byPath[e.path] == nil {
+				paths = append(paths, e.path)
+				byPath[e.path] = make(map[*apiVersion]http.HandlerFunc)
+			}
+			byPath[e.path][v] = handler
+		}
+	}
+
+	for _, path := range paths {
+		router.AddHandler(path, h.negotiated(byPath[path]))
+	}
+}
+
+// SweepReservations releases expired reservations every interval until
+// ctx is done.
+func (h *Handler) SweepReservations(ctx context.Context, interval time.Duration) {
+	h.reservations.Run(ctx, interval, h.logExpiredReservation)
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/servertest"
+)
+
+func TestHandler_Register(t *testing.T) {
+	first := servertest.NewRouter()
+	second := servertest.NewRouter()
+	newTestHandler(data.NewManager()).Register(first)
+	newTestHandler(data.NewManager()).Register(second)
+
+	body, _ := json.Marshal(testRecord)
+	rr := httptest.NewRecorder()
+	first.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/car", bytes.NewReader(body)))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected response code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	for _, tt := range []struct {
+		name     string
+		router   servertest.Router
+		target   string
+		wantCode int
+	}{
+		{"added car", first, "/car?id=123", http.StatusOK},
+		{"other api", second, "/car?id=123", http.StatusNotFound},
+		{"no snapshots", first, "/admin/snapshots", http.StatusNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			tt.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rr.Code != tt.wantCode {
+				t.Fatalf("Expected response code %v, got %v", tt.wantCode, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+const (
+	batchAdd    = "add"
+	batchUpdate = "update"
+	batchDelete = "delete"
+)
+
+// batchOperation is one step of POST /cars/batch. Add and update take the
+// car, delete takes its ID.
+type batchOperation struct {
+	Op  string   `json:"op"`
+	Car *carJSON `json:"car,omitempty"`
+	ID  string   `json:"id,omitempty"`
+}
+
+type batchRequest struct {
+	Operations []batchOperation `json:"operations"`
+}
+
+// batchResult is the outcome of an operation, the car as stored after an
+// add or an update.
+type batchResult struct {
+	Op  string   `json:"op"`
+	ID  string   `json:"id"`
+	Car *carJSON `json:"car,omitempty"`
+}
+
+// ErrorBatchOperation reports which operation made a batch fail.
+type ErrorBatchOperation struct {
+	Index int
+	Err   error
+}
+
+func (e ErrorBatchOperation) Error() string {
+	return fmt.Sprintf("operation %d: %s", e.Index, e.Err.Error())
+}
+
+type ErrorInvalidOperation struct {
+	Reason string
+}
+
+func (e ErrorInvalidOperation) Error() string {
+	return fmt.Sprintf("invalid operation: %s", e.Reason)
+}
+
+func (h *Handler) POSTCarsBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowedError(w, r)
+		return
+	}
+
+	var request batchRequest
+	if !h.decodeBody(w, r, &request) {
+		return
+	}
+
+	results := make([]batchResult, 0, len(request.Operations))
+	err := h.cars.Tx(r.Context(), func(tx data.Tx) error {
+		for i, operation := range request.Operations {
+			result, err := h.applyOperation(tx, operation)
+			if err != nil {
+				return ErrorBatchOperation{i, err}
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		h.batchError(w, r, err)
+		return
+	}
+
+	h.logger.Info(fmt.Sprintf("applied batch of %d operations", len(results)))
+	h.writeJSON(w, r, results)
+}
+
+func (h *Handler) applyOperation(tx data.Tx, operation batchOperation) (batchResult, error) {
+	result := batchResult{Op: operation.Op}
+
+	switch operation.Op {
+	case batchAdd, batchUpdate:
+		if operation.Car == nil {
+			return batchResult{}, ErrorInvalidOperation{fmt.Sprintf("'%s' requires a car", operation.Op)}
+		}
+		record, err := h.version.fromRequest(*operation.Car)
+		if err != nil {
+			return batchResult{}, err
+		}
+		if operation.Op == batchAdd {
+			if record.ID == "" {
+				record.ID = car.NewID()
+			}
+			if err := CheckID(record.ID); err != nil {
+				return batchResult{}, err
+			}
+			err = tx.Add(record)
+		} else {
+			err = tx.Update(record)
+		}
+		if err != nil {
+			return batchResult{}, err
+		}
+		stored, err := tx.Get(record.ID)
+		if err != nil {
+			return batchResult{}, err
+		}
+		versioned, err := h.version.marshal(stored)
+		if err != nil {
+			return batchResult{}, err
+		}
+		result.ID, result.Car = stored.ID, &versioned
+	case batchDelete:
+		if err := tx.Delete(operation.ID); err != nil {
+			return batchResult{}, err
+		}
+		result.ID = operation.ID
+	default:
+		return batchResult{}, ErrorInvalidOperation{fmt.Sprintf("unknown op '%s'", operation.Op)}
+	}
+	return result, nil
+}
+
+func (h *Handler) batchError(w http.ResponseWriter, r *http.Request, err error) {
+	cause := err
+	if batchErr, ok := err.(ErrorBatchOperation); ok {
+		cause = batchErr.Err
+	}
+
+	switch cause.(type) {
+	case ErrorInvalidOperation, ErrorInvalidBody, ErrorReservedID, car.ErrorFieldInvalid, car.ErrorFieldMissing,
+		data.ErrorAlreadyExists, data.ErrorVINAlreadyExists, data.ErrorInitialStatus:
+		h.logger.WarnContext(r.Context(), err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+	case data.ErrorRecordNotFound:
+		h.logger.WarnContext(r.Context(), err.Error())
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(err.Error()))
+	case data.ErrorIllegalTransition:
+		h.logger.WarnContext(r.Context(), err.Error())
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(err.Error()))
+	default:
+		h.unexpectedError(w, r, "error applying batch", err)
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+// jsonCar returns record as the car of a batch operation.
+func jsonCar(record car.Record) *carJSON {
+	body, _ := json.Marshal(record)
+	c := carJSON(body)
+	return &c
+}
+
+func TestPOSTCarsBatch(t *testing.T) {
+	ctx := context.Background()
+
+	other := testRecord
+	other.ID = "456"
+	other.Price = car.NewMoney(20000, "USD")
+
+	swapped := testRecord
+	swapped.Price = other.Price
+
+	tests := []struct {
+		name     string
+		body     any
+		wantCode int
+		wantN    int
+	}{
+		{
+			name: "atomic batch",
+			body: batchRequest{Operations: []batchOperation{
+				{Op: batchUpdate, Car: jsonCar(swapped)},
+				{Op: batchDelete, ID: other.ID},
+				{Op: batchAdd, Car: jsonCar(car.Record{ID: "789", Make: "Honda", Model: "Civic", Category: "Sedan", Package: "Standard", Color: "Red", Year: 2020, Mileage: 10, Price: car.NewMoney(1000, "USD")})},
+			}},
+			wantCode: http.StatusOK,
+			wantN:    3,
+		},
+		{
+			name: "failing operation rolls back the batch",
+			body: batchRequest{Operations: []batchOperation{
+				{Op: batchUpdate, Car: jsonCar(swapped)},
+				{Op: batchDelete, ID: "missing"},
+			}},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "unknown operation",
+			body:     batchRequest{Operations: []batchOperation{{Op: batchUpdate, Car: jsonCar(swapped)}, {Op: "merge"}}},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "malformed body",
+			body:     "operations",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cars := data.NewManager()
+			h := newTestHandler(cars)
+			_ = cars.Add(ctx, testRecord)
+			_ = cars.Add(ctx, other)
+
+			body, _ := json.Marshal(tt.body)
+			req, err := http.NewRequest(http.MethodPost, "/cars/batch", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(h.POSTCarsBatch)
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("Expected response code %v, got %v: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+
+			stored, _ := cars.Get(ctx, testRecord.ID)
+			if tt.wantCode != http.StatusOK {
+				if records, _ := cars.List(ctx); stored.Price != testRecord.Price || len(records) != 2 {
+					t.Fatalf("Expected failed batch to leave the store untouched, got: %v", records)
+				}
+				return
+			}
+
+			var results []batchResult
+			if err := json.Unmarshal(rr.Body.Bytes(), &results); err != nil {
+				t.Fatalf("Failed unmarshalling response: %v", err)
+			}
+			if len(results) != tt.wantN {
+				t.Fatalf("Unexpected results: %v", results)
+			}
+			if stored.Price != swapped.Price {
+				t.Fatalf("Expected price to be updated, got: %v", stored.Price)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// defaultMaxBodySize bounds request bodies unless WithMaxBodySize says
+// otherwise.
+const defaultMaxBodySize = 1 << 20
+
+type ErrorUnsupportedMediaType struct {
+	ContentType string
+}
+
+func (e ErrorUnsupportedMediaType) Error() string {
+	return fmt.Sprintf("unsupported Content-Type '%s', expected %s", e.ContentType, jsonMediaType)
+}
+
+type ErrorBodyTooLarge struct {
+	Limit int64
+}
+
+func (e ErrorBodyTooLarge) Error() string {
+	return fmt.Sprintf("request body larger than %d bytes", e.Limit)
+}
+
+type ErrorInvalidBody struct {
+	Reason string
+}
+
+func (e ErrorInvalidBody) Error() string {
+	return fmt.Sprintf("error decoding body: %s", e.Reason)
+}
+
+/*
+readBody reads the JSON body of r, up to the maximum size of the Handler.
+A request without a Content-Type is taken as JSON, any other type than
+JSON or the media type of the version is refused.
+*/
+func (h *Handler) readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != jsonMediaType && mediaType != h.version.mediaType() {
+			return nil, ErrorUnsupportedMediaType{contentType}
+		}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, ErrorBodyTooLarge{tooLarge.Limit}
+	}
+	if err != nil {
+		return nil, ErrorInvalidBody{err.Error()}
+	}
+	return body, nil
+}
+
+// decodeStrict decodes the single JSON value of body into v, refusing
+// fields v does not have.
+func decodeStrict(body []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return ErrorInvalidBody{err.Error()}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return ErrorInvalidBody{"unexpected data after the JSON value"}
+	}
+	return nil
+}
+
+// decodeBody reads and strictly decodes the JSON body of r into v. The
+// request is answered when it cannot be, and false returned.
+func (h *Handler) decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	body, err := h.readBody(w, r)
+	if err == nil {
+		err = decodeStrict(body, v)
+	}
+	if err != nil {
+		h.bodyError(w, r, err)
+		return false
+	}
+	return true
+}
+
+// bodyError answers a request whose body could not be read or is invalid.
+func (h *Handler) bodyError(w http.ResponseWriter, r *http.Request, err error) {
+	h.logger.WarnContext(r.Context(), err.Error())
+	switch err.(type) {
+	case ErrorUnsupportedMediaType:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case ErrorBodyTooLarge:
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	w.Write([]byte(err.Error()))
+}
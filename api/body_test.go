@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/servertest"
+)
+
+func TestStrictBodies(t *testing.T) {
+	body, _ := json.Marshal(testRecord)
+	valid := string(body)
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		wantCode    int
+		wantBody    string
+	}{
+		{"valid", http.MethodPost, "/car", "application/json; charset=utf-8", valid, http.StatusCreated, ""},
+		{"no content type", http.MethodPost, "/car", "", valid, http.StatusCreated, ""},
+		{"unknown field", http.MethodPost, "/car", "", strings.Replace(valid, `"color"`, `"colour"`, 1), http.StatusBadRequest, `error decoding body: json: unknown field "colour"`},
+		{"unknown price field", http.MethodPost, "/car", "", strings.Replace(valid, `"currency"`, `"curency"`, 1), http.StatusBadRequest, "error decoding body"},
+		{"trailing data", http.MethodPut, "/car", "", valid + "{}", http.StatusBadRequest, "error decoding body: unexpected data after the JSON value"},
+		{"wrong content type", http.MethodPut, "/car", "text/plain", valid, http.StatusUnsupportedMediaType, "unsupported Content-Type 'text/plain', expected application/json"},
+		{"too large", http.MethodPut, "/car", "", valid + strings.Repeat(" ", 1024), http.StatusRequestEntityTooLarge, "request body larger than 1024 bytes"},
+		{"batch", http.MethodPost, "/cars/batch", "", `{"operations": [], "dry_run": true}`, http.StatusBadRequest, `error decoding body: json: unknown field "dry_run"`},
+		{"reservation", http.MethodPost, "/reservation?id=123", "application/xml", `<holder/>`, http.StatusUnsupportedMediaType, ""},
+		{"term", http.MethodPost, "/makes", "", `{"name": "Kia"} trailing`, http.StatusBadRequest, "error decoding body"},
+	}
+	for _, tt := range tests {
+		for _, validation := range []bool{false, true} {
+			opts := []Option{WithMaxBodySize(1024)}
+			if validation {
+				opts = append(opts, WithRequestValidation())
+			}
+			router := servertest.NewRouter()
+			newTestHandler(data.NewManager(), opts...).Register(router)
+
+			t.Run(tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+				if tt.contentType != "" {
+					req.Header.Set("Content-Type", tt.contentType)
+				}
+				rr := httptest.NewRecorder()
+				router.ServeHTTP(rr, req)
+				// Validation refuses invalid bodies first, in its own words.
+				if rr.Code != tt.wantCode || !validation && !strings.HasPrefix(rr.Body.String(), tt.wantBody) {
+					t.Fatalf("validation %v: expected %v %q, got %v %q", validation, tt.wantCode, tt.wantBody, rr.Code, rr.Body.String())
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// The /car routes, replaced by the /cars/{id} ones, are deprecated since
+// deprecatedSince and may be removed after sunset.
+var (
+	deprecatedSince = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	sunset          = time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC)
+)
+
+// deprecationHeaders are set on every response of a deprecated route.
+var deprecationHeaders = []string{"Deprecation", "Sunset", "Link"}
+
+/*
+deprecated marks the operations of e as deprecated, their path parameters
+read from the query string instead, and answers its requests with the
+Deprecation (RFC 9745) and Sunset (RFC 8594) headers, linking to the
+OpenAPI document.
+*/
+func deprecated(e endpoint) endpoint {
+	operations := make([]operation, 0, len(e.operations))
+	for _, o := range e.operations {
+		params := make([]param, 0, len(o.params))
+		for _, p := range o.params {
+			if p.in == "path" {
+				p.in = "query"
+			}
+			params = append(params, p)
+		}
+		o.params = params
+		o.deprecated = true
+		operations = append(operations, o)
+	}
+
+	next := e.handler
+	return endpoint{e.path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", fmt.Sprintf("@%d", deprecatedSince.Unix()))
+		w.Header().Set("Sunset", sunset.Format(http.TimeFormat))
+		w.Header().Set("Link", `</openapi.json>; rel="deprecation"; type="application/json"`)
+		next(w, r)
+	}, operations}
+}
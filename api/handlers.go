@@ -0,0 +1,346 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/idempotency"
+)
+
+const internalServerErrorMessage = "unexpected internal error, please retry later"
+
+func (h *Handler) carsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GETCars(w, r)
+	case http.MethodPost:
+		h.POSTCar(w, r)
+	default:
+		methodNotAllowedError(w, r)
+	}
+}
+
+func (h *Handler) carHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.POSTCar(w, r)
+	case http.MethodGet:
+		h.GETCar(w, r)
+	case http.MethodPut:
+		h.PUTCar(w, r)
+	case http.MethodDelete:
+		h.DELETECar(w, r)
+	default:
+		methodNotAllowedError(w, r)
+	}
+}
+
+func (h *Handler) POSTCar(w http.ResponseWriter, r *http.Request) {
+	body, err := h.readBody(w, r)
+	if err != nil {
+		h.bodyError(w, r, err)
+		return
+	}
+
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" {
+		writeResponse(w, h.addCar(r, body))
+		return
+	}
+
+	// The response is in the shape of the version, a retry with the key in
+	// another version is a different request.
+	fingerprint := idempotency.Fingerprint(append([]byte(h.version.name), body...))
+	response, replayed, err := h.idempotency.Do(key, fingerprint, func() idempotency.Response {
+		return h.addCar(r, body)
+	})
+	if err != nil {
+		h.logger.WarnContext(r.Context(), err.Error())
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if replayed {
+		h.logger.Info(fmt.Sprintf("replayed response for idempotency key: '%s'", key))
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+	writeResponse(w, response)
+}
+
+// addCar stores the car in body, minting its ID when missing.
+func (h *Handler) addCar(r *http.Request, body []byte) idempotency.Response {
+	record, err := h.version.fromRequest(body)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), err.Error())
+		return textResponse(http.StatusBadRequest, err.Error())
+	}
+
+	if record.ID == "" {
+		record.ID = car.NewID()
+	}
+	if err := CheckID(record.ID); err != nil {
+		h.logger.WarnContext(r.Context(), err.Error())
+		return textResponse(http.StatusBadRequest, err.Error())
+	}
+
+	err = h.cars.Add(r.Context(), record)
+	if err != nil {
+		_, invalid := err.(car.ErrorFieldInvalid)
+		_, missing := err.(car.ErrorFieldMissing)
+		_, alreadyExists := err.(data.ErrorAlreadyExists)
+		_, vinExists := err.(data.ErrorVINAlreadyExists)
+		_, initialStatus := err.(data.ErrorInitialStatus)
+		if invalid || missing || initialStatus {
+			h.logger.WarnContext(r.Context(), err.Error())
+			return textResponse(http.StatusBadRequest, err.Error())
+		} else if alreadyExists || vinExists {
+			h.logger.WarnContext(r.Context(), err.Error())
+			return textResponse(http.StatusBadRequest, err.Error())
+		}
+		if contextError(err) {
+			h.logger.WarnContext(r.Context(), fmt.Sprintf("error adding car: %s", err.Error()))
+			return textResponse(http.StatusServiceUnavailable, err.Error())
+		}
+		h.logger.ErrorContext(r.Context(), fmt.Sprintf("error adding car: %s", err.Error()))
+		return textResponse(http.StatusInternalServerError, internalServerErrorMessage)
+	}
+
+	// Respond with the record as stored, normalized by the manager.
+	record, err = h.cars.Get(r.Context(), record.ID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), fmt.Sprintf("error getting added car: %s", err.Error()))
+		return textResponse(http.StatusInternalServerError, internalServerErrorMessage)
+	}
+
+	jsonRecord, err := json.Marshal(h.record(record))
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), fmt.Sprintf("error marshalling record: %s", err.Error()))
+		return textResponse(http.StatusInternalServerError, internalServerErrorMessage)
+	}
+
+	h.logger.Info(fmt.Sprintf("added new car with id: '%s'", record.ID))
+	return idempotency.Response{
+		Status: http.StatusCreated,
+		Header: http.Header{
+			"Content-Type": {h.contentType(r)},
+			"Location":     {"/cars/" + url.PathEscape(record.ID)},
+		},
+		Body: jsonRecord,
+	}
+}
+
+func (h *Handler) GETCars(w http.ResponseWriter, r *http.Request) {
+	query, err := parseCarsQuery(r.URL.Query())
+	if err != nil {
+		h.logger.WarnContext(r.Context(), err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	unit, err := requestedUnit(r)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	query.MileageUnit = unit
+
+	records, err := h.cars.Query(r.Context(), query)
+	if err != nil {
+		h.unexpectedError(w, r, "error listing cars", err)
+		return
+	}
+
+	if unit != "" {
+		for i := range records {
+			records[i] = records[i].WithMileageIn(unit)
+		}
+	}
+
+	if currency := strings.ToUpper(r.URL.Query().Get("convert")); currency != "" {
+		for i := range records {
+			records[i].Price, err = h.rates.Convert(records[i].Price, currency)
+			if err != nil {
+				h.logger.WarnContext(r.Context(), err.Error())
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(err.Error()))
+				return
+			}
+		}
+	}
+
+	h.logger.Info(fmt.Sprintf("listing %v cars", len(records)))
+	versioned := make([]any, 0, len(records))
+	for _, record := range records {
+		versioned = append(versioned, h.record(record))
+	}
+	jsonRecords, err := json.Marshal(versioned)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), fmt.Sprintf("error marshalling records: %s", err.Error()))
+		internalServerError(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", h.contentType(r))
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonRecords)
+}
+
+func (h *Handler) GETCar(w http.ResponseWriter, r *http.Request) {
+	id := carID(r)
+	vin := strings.ToUpper(r.URL.Query().Get("vin"))
+
+	unit, err := requestedUnit(r)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	var record car.Record
+	if id == "" && vin != "" {
+		record, err = h.cars.GetByVIN(r.Context(), vin)
+	} else {
+		record, err = h.cars.Get(r.Context(), id)
+	}
+	if err != nil {
+		_, invalid := err.(car.ErrorFieldInvalid)
+		_, missing := err.(car.ErrorFieldMissing)
+		_, notFound := err.(data.ErrorRecordNotFound)
+		_, vinNotFound := err.(data.ErrorVINNotFound)
+		if invalid || missing {
+			h.logger.WarnContext(r.Context(), err.Error())
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+		} else if notFound || vinNotFound {
+			h.logger.WarnContext(r.Context(), err.Error())
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(err.Error()))
+		} else {
+			h.unexpectedError(w, r, "error getting car", err)
+		}
+		return
+	}
+
+	h.logger.Info(fmt.Sprintf("found car with id: '%s'", record.ID))
+	if unit != "" {
+		record = record.WithMileageIn(unit)
+	}
+	jsonRecord, err := json.Marshal(h.record(record))
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), fmt.Sprintf("error marshalling record: %s", err.Error()))
+		internalServerError(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", h.contentType(r))
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonRecord)
+}
+
+func (h *Handler) PUTCar(w http.ResponseWriter, r *http.Request) {
+	record, ok := h.decodeRecord(w, r)
+	if !ok {
+		return
+	}
+	h.updateCar(w, r, record)
+}
+
+// updateCar replaces a car with record, taking its ID from the path when
+// the route has one.
+func (h *Handler) updateCar(w http.ResponseWriter, r *http.Request, record car.Record) {
+	err := bindID(r, &record)
+	if err == nil {
+		err = h.cars.Update(r.Context(), record)
+	}
+	h.updated(w, r, record.ID, err)
+}
+
+// updated answers a request replacing the car with the given ID, which
+// failed with err unless it is nil.
+func (h *Handler) updated(w http.ResponseWriter, r *http.Request, id string, err error) {
+	if err != nil {
+		_, mismatch := err.(ErrorIDMismatch)
+		_, invalid := err.(car.ErrorFieldInvalid)
+		_, missing := err.(car.ErrorFieldMissing)
+		_, notFound := err.(data.ErrorRecordNotFound)
+		_, vinExists := err.(data.ErrorVINAlreadyExists)
+		_, illegal := err.(data.ErrorIllegalTransition)
+		if mismatch || invalid || missing || vinExists {
+			h.logger.WarnContext(r.Context(), err.Error())
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+		} else if notFound {
+			h.logger.WarnContext(r.Context(), err.Error())
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(err.Error()))
+		} else if illegal {
+			h.logger.WarnContext(r.Context(), err.Error())
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(err.Error()))
+		} else {
+			h.unexpectedError(w, r, "error updating car", err)
+		}
+		return
+	}
+
+	h.logger.Info(fmt.Sprintf("updated car with id: '%s'", id))
+	w.WriteHeader(http.StatusAccepted)
+	w.Write([]byte(fmt.Sprintf("updated car '%s'", id)))
+}
+
+func methodNotAllowedError(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusMethodNotAllowed)
+	w.Write([]byte(fmt.Sprintf("Method %s not allowed", r.Method)))
+}
+
+func internalServerError(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusInternalServerError)
+	w.Write([]byte(internalServerErrorMessage))
+}
+
+/*
+unexpectedError responds to an error the handler has no specific status
+for. The request being cancelled or running out of time is not a fault
+of the server, it is logged as a warning and answered with 503.
+*/
+func (h *Handler) unexpectedError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	if contextError(err) {
+		h.logger.WarnContext(r.Context(), fmt.Sprintf("%s: %s", message, err.Error()))
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	h.logger.ErrorContext(r.Context(), fmt.Sprintf("%s: %s", message, err.Error()))
+	internalServerError(w, r)
+}
+
+// contextError reports whether err comes from a cancelled or expired
+// context.
+func contextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func textResponse(status int, message string) idempotency.Response {
+	return idempotency.Response{Status: status, Body: []byte(message)}
+}
+
+func writeResponse(w http.ResponseWriter, response idempotency.Response) {
+	for name, values := range response.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	w.WriteHeader(response.Status)
+	w.Write(response.Body)
+}
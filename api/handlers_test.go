@@ -0,0 +1,408 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/exchange"
+)
+
+var testRecord = car.Record{
+	ID:       "123",
+	Make:     "Toyota",
+	Model:    "Camry",
+	Category: "Sedan",
+	Package:  "Standard",
+	Color:    "Blue",
+	Year:     time.Now().Year(),
+	Mileage:  1000,
+	Price:    car.NewMoney(10000, "USD"),
+	Status:   car.StatusAvailable,
+}
+
+// newTestHandler returns a Handler for cars logging to the default logger.
+func newTestHandler(cars data.Manager, opts ...Option) *Handler {
+	return NewHandler(cars, slog.Default(), clock.System, opts...)
+}
+
+func TestPOSTCars(t *testing.T) {
+	cars := data.NewManager()
+	h := newTestHandler(cars)
+	body, _ := json.Marshal(testRecord)
+
+	req, err := http.NewRequest(http.MethodPost, "/car", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(h.POSTCar)
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Errorf("Expected response code %v, got: %v", http.StatusCreated, rr.Code)
+	}
+
+	expectedLocation := fmt.Sprintf("/cars/%s", testRecord.ID)
+	if rr.Header().Get("Location") != expectedLocation {
+		t.Errorf("Expected location: %v, got: %v", expectedLocation, rr.Header().Get("Location"))
+	}
+
+	var gotRecord car.Record
+	err = json.Unmarshal(rr.Body.Bytes(), &gotRecord)
+	if err != nil {
+		t.Fatalf("Failed unmarshalling response: %v", err)
+	}
+
+	if !reflect.DeepEqual(gotRecord, testRecord) {
+		t.Fatalf("Unexpected record obtained, wanted: %v, got: %v", testRecord, gotRecord)
+	}
+}
+
+func TestPOSTCarsNotAvailable(t *testing.T) {
+	h := newTestHandler(data.NewManager())
+	record := testRecord
+	record.Status = car.StatusSold
+	body, _ := json.Marshal(record)
+
+	rr := httptest.NewRecorder()
+	h.POSTCar(rr, httptest.NewRequest(http.MethodPost, "/car", bytes.NewBuffer(body)))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("Expected response code %v, got: %v", http.StatusBadRequest, rr.Code)
+	}
+	if want := (data.ErrorInitialStatus{ID: record.ID, Status: car.StatusSold}).Error(); rr.Body.String() != want {
+		t.Fatalf("Expected body %q, got: %q", want, rr.Body.String())
+	}
+}
+
+func TestPOSTCarsGeneratedID(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	h := newTestHandler(cars)
+	record := testRecord
+	record.ID = ""
+	body, _ := json.Marshal(record)
+
+	req, err := http.NewRequest(http.MethodPost, "/car", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(h.POSTCar)
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected response code %v, got: %v", http.StatusCreated, rr.Code)
+	}
+
+	var gotRecord car.Record
+	err = json.Unmarshal(rr.Body.Bytes(), &gotRecord)
+	if err != nil {
+		t.Fatalf("Failed unmarshalling response: %v", err)
+	}
+
+	if gotRecord.ID == "" {
+		t.Fatal("Expected server to generate an ID")
+	}
+	if _, err := cars.Get(ctx, gotRecord.ID); err != nil {
+		t.Fatalf("Expected car to be stored with generated ID: %v", err)
+	}
+}
+
+func TestPOSTCarsIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	h := newTestHandler(cars, WithIdempotencyTTL(time.Hour))
+	record := testRecord
+	record.ID = ""
+	body, _ := json.Marshal(record)
+
+	post := func(body []byte) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, "/car", bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Idempotency-Key", "retry-me")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(h.POSTCar)
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	first := post(body)
+	retry := post(body)
+
+	if retry.Code != http.StatusCreated {
+		t.Fatalf("Expected response code %v on retry, got: %v", http.StatusCreated, retry.Code)
+	}
+	if retry.Header().Get("Location") != first.Header().Get("Location") {
+		t.Fatalf("Expected retry to return original location %v, got: %v", first.Header().Get("Location"), retry.Header().Get("Location"))
+	}
+	if retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatal("Expected retry to be marked as replayed")
+	}
+	if records, _ := cars.List(ctx); len(records) != 1 {
+		t.Fatalf("Expected one car to be stored, got: %v", len(records))
+	}
+
+	record.Color = "Red"
+	otherBody, _ := json.Marshal(record)
+	if rr := post(otherBody); rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected response code %v when reusing key, got: %v", http.StatusUnprocessableEntity, rr.Code)
+	}
+}
+
+func TestGETCar(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	h := newTestHandler(cars)
+	_ = cars.Add(ctx, testRecord)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/car?id=%s", testRecord.ID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(h.GETCar)
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected response code %v, got %v", http.StatusOK, rr.Code)
+	}
+
+	var gotRecord car.Record
+	err = json.Unmarshal(rr.Body.Bytes(), &gotRecord)
+	if err != nil {
+		t.Fatalf("Failed unmarshalling response: %v", err)
+	}
+
+	if !reflect.DeepEqual(gotRecord, testRecord) {
+		t.Fatalf("Unexpected record obtained, wanted: %v, got: %v", testRecord, gotRecord)
+	}
+}
+
+func TestGETCarByVIN(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	h := newTestHandler(cars)
+	record := testRecord
+	record.Make = "Honda"
+	record.Year = 2003
+	record.VIN = "1HGCM82633A004352"
+	_ = cars.Add(ctx, record)
+
+	req, err := http.NewRequest(http.MethodGet, "/car?vin=1hgcm82633a004352", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(h.GETCar)
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected response code %v, got %v", http.StatusOK, rr.Code)
+	}
+
+	var gotRecord car.Record
+	err = json.Unmarshal(rr.Body.Bytes(), &gotRecord)
+	if err != nil {
+		t.Fatalf("Failed unmarshalling response: %v", err)
+	}
+
+	if !reflect.DeepEqual(gotRecord, record) {
+		t.Fatalf("Unexpected record obtained, wanted: %v, got: %v", record, gotRecord)
+	}
+}
+
+func TestGETCarUnits(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	h := newTestHandler(cars)
+	_ = cars.Add(ctx, testRecord)
+
+	tests := []struct {
+		name        string
+		target      string
+		acceptUnits string
+		wantCode    int
+		wantMileage int
+		wantUnit    car.DistanceUnit
+	}{
+		{"as stored", "/car?id=123", "", http.StatusOK, 1000, ""},
+		{"query parameter", "/car?id=123&units=km", "", http.StatusOK, 1609, car.Kilometers},
+		{"header", "/car?id=123", "km", http.StatusOK, 1609, car.Kilometers},
+		{"query parameter wins", "/car?id=123&units=mi", "km", http.StatusOK, 1000, car.Miles},
+		{"invalid unit", "/car?id=123&units=leagues", "", http.StatusBadRequest, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.target, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.acceptUnits != "" {
+				req.Header.Set("Accept-Units", tt.acceptUnits)
+			}
+
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(h.GETCar)
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("Expected response code %v, got %v", tt.wantCode, rr.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var gotRecord car.Record
+			err = json.Unmarshal(rr.Body.Bytes(), &gotRecord)
+			if err != nil {
+				t.Fatalf("Failed unmarshalling response: %v", err)
+			}
+			if gotRecord.Mileage != tt.wantMileage || gotRecord.MileageUnit != tt.wantUnit {
+				t.Fatalf("Unexpected mileage, wanted: %v %v, got: %v %v", tt.wantMileage, tt.wantUnit, gotRecord.Mileage, gotRecord.MileageUnit)
+			}
+		})
+	}
+}
+
+func TestGETCars(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	h := newTestHandler(cars)
+	_ = cars.Add(ctx, testRecord)
+	req, err := http.NewRequest(http.MethodGet, "/cars", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(h.GETCars)
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected response code %v, got %v", http.StatusOK, rr.Code)
+	}
+
+	var gotRecords []car.Record
+	err = json.Unmarshal(rr.Body.Bytes(), &gotRecords)
+	if err != nil {
+		t.Fatalf("Failed unmarshalling response: %v", err)
+	}
+
+	wantRecords := []car.Record{testRecord}
+	if !reflect.DeepEqual(gotRecords, wantRecords) {
+		t.Fatalf("Unexpected record obtained, wanted: %v, got: %v", wantRecords, gotRecords)
+	}
+}
+
+func TestGETCarsCancelled(t *testing.T) {
+	cars := data.NewManager()
+	h := newTestHandler(cars)
+	_ = cars.Add(context.Background(), testRecord)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/cars", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(h.GETCars)
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected response code %v, got %v", http.StatusServiceUnavailable, rr.Code)
+	}
+}
+
+func TestGETCarsConvert(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	rates, _ := exchange.New("USD", map[string]string{"EUR": "0.5"})
+	h := newTestHandler(cars, WithExchangeRates(rates))
+	_ = cars.Add(ctx, testRecord)
+
+	req, err := http.NewRequest(http.MethodGet, "/cars?convert=eur", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(h.GETCars)
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected response code %v, got %v", http.StatusOK, rr.Code)
+	}
+
+	var gotRecords []recordV1
+	err = json.Unmarshal(rr.Body.Bytes(), &gotRecords)
+	if err != nil {
+		t.Fatalf("Failed unmarshalling response: %v", err)
+	}
+
+	if len(gotRecords) != 1 || string(gotRecords[0].Price) != "5000" || gotRecords[0].Currency != "EUR" {
+		t.Fatalf("Unexpected records obtained, wanted price: 5000 EUR, got: %v", gotRecords)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/cars?convert=GBP", nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("Expected response code %v for missing rate, got %v", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestPUTCar(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	h := newTestHandler(cars)
+	_ = cars.Add(ctx, testRecord)
+
+	updatedRecord := testRecord
+	updatedRecord.Make = "Mitsubishi"
+
+	body, _ := json.Marshal(updatedRecord)
+
+	req, err := http.NewRequest(http.MethodPut, "/cars", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(h.PUTCar)
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusAccepted {
+		t.Errorf("Expected response code %v, got %v", http.StatusAccepted, rr.Code)
+	}
+}
@@ -0,0 +1,533 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/openapi"
+	"github.com/YoungOak/GoAPI/internal/reservation"
+	"github.com/YoungOak/GoAPI/snapshot"
+)
+
+const title = "Cars API"
+
+// endpoint is a route of the API with the operations it serves. Register
+// and the OpenAPI document are both built from the endpoints of the
+// Handler, so the document cannot miss a route.
+type endpoint struct {
+	path       string
+	handler    http.HandlerFunc
+	operations []operation
+}
+
+// operation documents one method of an endpoint.
+type operation struct {
+	method     string
+	summary    string
+	deprecated bool
+	// admin operations require the admin token.
+	admin  bool
+	params []param
+	// body is a value of the type of the JSON request body, nil when the
+	// operation takes none.
+	body      any
+	responses []response
+}
+
+type param struct {
+	name        string
+	in          string
+	description string
+	required    bool
+	// schema is a value of the type of the parameter, or an
+	// *openapi.Schema.
+	schema any
+}
+
+type response struct {
+	status      int
+	description string
+	// body is a value of the type of the JSON response body, a string for
+	// plain text and nil when the response has none.
+	body    any
+	headers []string
+}
+
+// headerDescriptions documents the headers of responses.
+var headerDescriptions = map[string]string{
+	"Location":            "Path of the created car",
+	"Idempotent-Replayed": "true when the response was stored for the Idempotency-Key of an earlier request",
+	"Deprecation":         "When the route was deprecated, as @ and Unix seconds",
+	"Sunset":              "Date after which the route may be removed",
+	"Link":                "The OpenAPI document, which lists the routes replacing the deprecated ones",
+}
+
+// errorDescriptions documents the plain text error responses, operations
+// only list their status.
+var errorDescriptions = map[int]string{
+	http.StatusBadRequest:            "The request is invalid, the body tells why",
+	http.StatusUnauthorized:          "The admin token is missing or wrong",
+	http.StatusNotFound:              "The car or the resource asked for does not exist",
+	http.StatusConflict:              "The request conflicts with the status of the car",
+	http.StatusRequestEntityTooLarge: "The request body is larger than the configured limit",
+	http.StatusUnsupportedMediaType:  "The request body is not sent as application/json",
+	http.StatusUnprocessableEntity:   "The request cannot be applied, the body tells why",
+	http.StatusInternalServerError:   internalServerErrorMessage,
+	http.StatusServiceUnavailable:    "The request was cancelled or timed out before it completed",
+}
+
+func idParam(description string) param {
+	return param{name: "id", in: "path", description: description, required: true, schema: ""}
+}
+
+var unitParams = []param{
+	{name: "units", in: "query", description: "Odometer unit of the response, and of the mileage bounds", schema: car.DistanceUnit("")},
+	{name: "Accept-Units", in: "header", description: "Odometer unit of the response when units is not given", schema: car.DistanceUnit("")},
+}
+
+// failures are the error responses of statuses.
+func failures(statuses ...int) []response {
+	responses := make([]response, 0, len(statuses))
+	for _, status := range statuses {
+		responses = append(responses, response{status: status, description: errorDescriptions[status], body: ""})
+	}
+	return responses
+}
+
+// endpoints lists the routes of the version of the Handler, v1 also
+// serving the deprecated /car routes. The snapshot endpoints are only
+// served when the Handler has a snapshot store and an admin token.
+func (h *Handler) endpoints() []endpoint {
+	postCar := operation{
+		method:  http.MethodPost,
+		summary: "Add a car, with a generated ID when it has none",
+		params: []param{
+			{name: "Idempotency-Key", in: "header", description: "Key under which the response is stored, a retry with the same key and body gets it again instead of adding the car twice", schema: ""},
+		},
+		body: carJSON{},
+		responses: append([]response{
+			{status: http.StatusCreated, description: "The car as stored", body: carJSON{}, headers: []string{"Location", "Idempotent-Replayed"}},
+		}, failures(http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+	}
+	updateCarResponses := append([]response{
+		{status: http.StatusAccepted, description: "The car was updated", body: ""},
+	}, failures(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError, http.StatusServiceUnavailable)...)
+	deleteCar := operation{
+		method:  http.MethodDelete,
+		summary: "Move a car to the trash",
+		params:  []param{idParam("ID of the car")},
+		responses: append([]response{
+			{status: http.StatusNoContent, description: "The car was moved to the trash"},
+		}, failures(http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+	}
+
+	endpoints := []endpoint{
+		{"/cars", h.carsHandler, []operation{{
+			method:  http.MethodGet,
+			summary: "List the cars, optionally filtered, sorted and paginated",
+			params: append([]param{
+				{name: "make", in: "query", description: "Only cars of this make", schema: ""},
+				{name: "model", in: "query", description: "Only cars of this model", schema: ""},
+				{name: "category", in: "query", description: "Only cars of this category", schema: ""},
+				{name: "color", in: "query", description: "Only cars of this color", schema: ""},
+				{name: "vin", in: "query", description: "Only the car with this VIN", schema: ""},
+				{name: "min_year", in: "query", description: "Minimum year, inclusive", schema: 0},
+				{name: "max_year", in: "query", description: "Maximum year, inclusive", schema: 0},
+				{name: "currency", in: "query", description: "Only cars priced in this ISO 4217 currency", schema: ""},
+				{name: "status", in: "query", description: "Only cars in this status", schema: car.Status("")},
+				{name: "price_dropped_since", in: "query", description: "Only cars whose price was lowered in the same currency at or after this RFC 3339 timestamp or date, and not raised back since", schema: ""},
+				{name: "convert", in: "query", description: "ISO 4217 currency to convert prices to using the configured exchange rates", schema: ""},
+				{name: "min_price", in: "query", description: "Minimum price in whole units of the currency filtered on, which it requires, inclusive", schema: 0.0},
+				{name: "max_price", in: "query", description: "Maximum price in whole units of the currency filtered on, which it requires, inclusive", schema: 0.0},
+				{name: "min_mileage", in: "query", description: "Minimum mileage in the requested units, miles by default, inclusive", schema: 0},
+				{name: "max_mileage", in: "query", description: "Maximum mileage in the requested units, miles by default, inclusive", schema: 0},
+				{name: "sort", in: "query", description: "Field to sort by, id by default", schema: data.SortField("")},
+				{name: "order", in: "query", description: "Sort order, asc by default", schema: &openapi.Schema{Type: "string", Enum: []any{"asc", "desc"}}},
+				{name: "offset", in: "query", description: "Number of cars skipped", schema: nonNegative()},
+				{name: "limit", in: "query", description: "Maximum number of cars returned, 0 returns all", schema: nonNegative()},
+			}, unitParams...),
+			responses: append([]response{
+				{status: http.StatusOK, description: "The cars", body: []carJSON{}},
+			}, failures(http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+		}, postCar}},
+		{"/cars/{id}", h.carResourceHandler, []operation{
+			{
+				method:  http.MethodGet,
+				summary: "Get a car",
+				params:  append([]param{idParam("ID of the car")}, unitParams...),
+				responses: append([]response{
+					{status: http.StatusOK, description: "The car", body: carJSON{}},
+				}, failures(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+			},
+			{
+				method:    http.MethodPut,
+				summary:   "Replace a car, the ID of the body may be left out",
+				params:    []param{idParam("ID of the car")},
+				body:      carJSON{},
+				responses: updateCarResponses,
+			},
+			{
+				method:    http.MethodPatch,
+				summary:   "Change some fields of a car with a JSON merge patch, null removing a field",
+				params:    []param{idParam("ID of the car")},
+				body:      map[string]any{},
+				responses: updateCarResponses,
+			},
+			deleteCar,
+		}},
+		{"/cars/trash", h.GETTrash, []operation{{
+			method:  http.MethodGet,
+			summary: "List the deleted cars that can still be restored",
+			responses: append([]response{
+				{status: http.StatusOK, description: "The trashed cars", body: []trashedCar{}},
+			}, failures(http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+		}}},
+		{"/cars/trash/{id}/restore", h.POSTRestore, []operation{{
+			method:  http.MethodPost,
+			summary: "Restore a car from the trash",
+			params:  []param{idParam("ID of the trashed car")},
+			responses: append([]response{
+				{status: http.StatusOK, description: "The restored car", body: carJSON{}},
+			}, failures(http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+		}}},
+		{"/cars/batch", h.POSTCarsBatch, []operation{{
+			method:  http.MethodPost,
+			summary: "Apply operations atomically, all of them or none",
+			body:    batchRequest{},
+			responses: append([]response{
+				{status: http.StatusOK, description: "The outcome of each operation", body: []batchResult{}},
+			}, failures(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+		}}},
+	}
+
+	for _, transition := range []struct {
+		path    string
+		to      car.Status
+		summary string
+	}{
+		{"/cars/{id}/reserve", car.StatusReserved, "Mark a car as reserved"},
+		{"/cars/{id}/release", car.StatusAvailable, "Make a car available again"},
+		{"/cars/{id}/sell", car.StatusSold, "Mark a car as sold"},
+		{"/cars/{id}/service", car.StatusInService, "Take a car into service"},
+	} {
+		endpoints = append(endpoints, endpoint{transition.path, h.transitionHandler(transition.to), []operation{{
+			method:  http.MethodPost,
+			summary: transition.summary,
+			params:  []param{idParam("ID of the car")},
+			responses: append([]response{
+				{status: http.StatusOK, description: "The car in its new status", body: carJSON{}},
+			}, failures(http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+		}}})
+	}
+
+	endpoints = append(endpoints,
+		endpoint{"/cars/{id}/history", h.GETCarHistory, []operation{{
+			method:  http.MethodGet,
+			summary: "Get the statuses a car went through",
+			params:  []param{idParam("ID of the car")},
+			responses: append([]response{
+				{status: http.StatusOK, description: "The status changes, oldest first", body: []car.StatusChange{}},
+			}, failures(http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+		}}},
+		endpoint{"/cars/{id}/prices", h.GETCarPrices, []operation{{
+			method:  http.MethodGet,
+			summary: "Get the prices a car was listed at",
+			params:  []param{idParam("ID of the car")},
+			responses: append([]response{
+				{status: http.StatusOK, description: "The price changes, oldest first", body: []car.PriceChange{}},
+			}, failures(http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+		}}},
+		endpoint{"/reservations", h.reservationsHandler, []operation{{
+			method:  http.MethodGet,
+			summary: "List the active reservations",
+			responses: append([]response{
+				{status: http.StatusOK, description: "The reservations", body: []reservation.Reservation{}},
+			}, failures(http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+		}}},
+		endpoint{"/cars/{id}/reservation", h.reservationHandler, []operation{
+			{
+				method:  http.MethodPost,
+				summary: "Hold a car for a customer",
+				params:  []param{idParam("ID of the car")},
+				body:    reservationRequest{},
+				responses: append([]response{
+					{status: http.StatusCreated, description: "The reservation", body: reservation.Reservation{}},
+				}, failures(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+			},
+			{
+				method:  http.MethodGet,
+				summary: "Get the reservation of a car",
+				params:  []param{idParam("ID of the car")},
+				responses: append([]response{
+					{status: http.StatusOK, description: "The reservation", body: reservation.Reservation{}},
+				}, failures(http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+			},
+			{
+				method:  http.MethodDelete,
+				summary: "Release the reservation of a car",
+				params:  []param{idParam("ID of the car")},
+				responses: append([]response{
+					{status: http.StatusNoContent, description: "The car is available again"},
+				}, failures(http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+			},
+		}},
+	)
+
+	if h.snapshots != nil && h.adminToken != "" {
+		endpoints = append(endpoints,
+			h.admin(endpoint{"/admin/snapshots", h.snapshotsHandler, []operation{
+				{
+					method:  http.MethodGet,
+					summary: "List the snapshots, the most recent first",
+					responses: append([]response{
+						{status: http.StatusOK, description: "The snapshots", body: []snapshot.Info{}},
+					}, failures(http.StatusInternalServerError)...),
+				},
+				{
+					method:  http.MethodPost,
+					summary: "Save a snapshot of the store",
+					responses: append([]response{
+						{status: http.StatusCreated, description: "The saved snapshot", body: snapshot.Info{}},
+					}, failures(http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+				},
+			}}),
+			h.admin(endpoint{"/admin/snapshots/restore", h.POSTSnapshotRestore, []operation{{
+				method:  http.MethodPost,
+				summary: "Replace the store with a snapshot",
+				params:  []param{{name: "name", in: "query", description: "Name of the snapshot", required: true, schema: ""}},
+				responses: append([]response{
+					{status: http.StatusOK, description: "The snapshot was restored", body: ""},
+				}, failures(http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+			}}}),
+		)
+	}
+
+	// The routes before /cars/{id} are kept as deprecated aliases in v1,
+	// taking the ID as a query parameter.
+	if h.version == v1 {
+		endpoints = append(endpoints, deprecated(endpoint{"/car", h.carHandler, []operation{
+			{
+				method:  http.MethodGet,
+				summary: "Get a car by its ID, or by its VIN when no ID is given",
+				params: append([]param{
+					{name: "id", in: "query", description: "ID of the car", schema: ""},
+					{name: "vin", in: "query", description: "VIN of the car", schema: ""},
+				}, unitParams...),
+				responses: append([]response{
+					{status: http.StatusOK, description: "The car", body: carJSON{}},
+				}, failures(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable)...),
+			},
+			postCar,
+			{
+				method:    http.MethodPut,
+				summary:   "Replace a car",
+				body:      carJSON{},
+				responses: updateCarResponses,
+			},
+			deleteCar,
+		}}))
+		for _, alias := range []struct{ path, successor string }{
+			{"/car/restore", "/cars/trash/{id}/restore"},
+			{"/car/reserve", "/cars/{id}/reserve"},
+			{"/car/release", "/cars/{id}/release"},
+			{"/car/sell", "/cars/{id}/sell"},
+			{"/car/service", "/cars/{id}/service"},
+			{"/car/history", "/cars/{id}/history"},
+			{"/car/prices", "/cars/{id}/prices"},
+			{"/reservation", "/cars/{id}/reservation"},
+		} {
+			for _, e := range endpoints {
+				if e.path == alias.successor {
+					endpoints = append(endpoints, deprecated(endpoint{alias.path, e.handler, e.operations}))
+					break
+				}
+			}
+		}
+	}
+
+	makeParam := param{name: "make", in: "query", description: "Make of the models", required: true, schema: ""}
+	endpoints = append(endpoints,
+		endpoint{"/makes", h.makesHandler, vocabOperations("make")},
+		endpoint{"/models", h.modelsHandler, vocabOperations("model", makeParam)},
+		endpoint{"/categories", h.categoriesHandler, vocabOperations("category")},
+		endpoint{"/openapi.json", h.GETOpenAPI, []operation{{
+			method:  http.MethodGet,
+			summary: "Get this document",
+			responses: []response{
+				{status: http.StatusOK, description: "The OpenAPI document of the API", body: map[string]any{}},
+			},
+		}}},
+	)
+	return endpoints
+}
+
+// vocabOperations documents the operations on the terms of a kind, makes,
+// models or categories.
+func vocabOperations(kind string, params ...param) []operation {
+	nameParam := param{name: "name", in: "query", description: "Current name of the " + kind, required: true, schema: ""}
+	// Only models belong to a make that may not exist.
+	var listFailures []response
+	if len(params) > 0 {
+		listFailures = failures(http.StatusNotFound)
+	}
+	return []operation{
+		{
+			method:  http.MethodGet,
+			summary: "List the known " + kind + " names",
+			params:  params,
+			responses: append([]response{
+				{status: http.StatusOK, description: "The names", body: []string{}},
+			}, listFailures...),
+		},
+		{
+			method:  http.MethodPost,
+			summary: "Add a " + kind,
+			params:  params,
+			body:    termRequest{},
+			responses: append([]response{
+				{status: http.StatusCreated, description: "The " + kind + " was added", body: ""},
+			}, failures(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError)...),
+		},
+		{
+			method:  http.MethodPut,
+			summary: "Rename a " + kind,
+			params:  append(params[:len(params):len(params)], nameParam),
+			body:    termRequest{},
+			responses: append([]response{
+				{status: http.StatusAccepted, description: "The " + kind + " was renamed", body: ""},
+			}, failures(http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError)...),
+		},
+		{
+			method:  http.MethodDelete,
+			summary: "Delete a " + kind,
+			params:  append(params[:len(params):len(params)], nameParam),
+			responses: append([]response{
+				{status: http.StatusNoContent, description: "The " + kind + " was deleted"},
+			}, failures(http.StatusNotFound, http.StatusInternalServerError)...),
+		},
+	}
+}
+
+func nonNegative() *openapi.Schema {
+	zero := 0
+	return &openapi.Schema{Type: "integer", Minimum: &zero}
+}
+
+// OpenAPI returns the OpenAPI document of the endpoints of the version of
+// the Handler, its paths relative to the prefix of the version.
+func (h *Handler) OpenAPI() openapi.Document {
+	schemas := openapi.NewSchemas()
+	schemas.Enum(car.StatusAvailable, car.StatusReserved, car.StatusSold, car.StatusInService)
+	schemas.Enum(car.Miles, car.Kilometers)
+	schemas.Enum(data.SortByID, data.SortByYear, data.SortByPrice, data.SortByMileage)
+	schemas.Name(h.version.record, "CarRecord")
+	schemas.Alias(carJSON{}, h.version.record)
+	if h.version == v1 {
+		schemas.Of(car.Money{})
+		money := *schemas.Components()["Money"]
+		schemas.Define(priceV1{}, &openapi.Schema{OneOf: []*openapi.Schema{
+			{Type: "number", Description: "Whole units of the currency of the car"},
+			&money,
+		}})
+	}
+	schemas.Name(trashedCar{}, "TrashedRecord")
+	schemas.Name(batchRequest{}, "Batch")
+	schemas.Name(termRequest{}, "Term")
+	schemas.Name(snapshot.Info{}, "SnapshotInfo")
+
+	document := openapi.Document{
+		OpenAPI: openapi.Version,
+		Info:    openapi.Info{Title: title, Version: h.version.release},
+		Servers: []openapi.Server{{URL: "/" + h.version.name}},
+		Paths:   make(map[string]openapi.PathItem),
+	}
+	for _, e := range h.endpoints() {
+		item := make(openapi.PathItem, len(e.operations))
+		for _, o := range e.operations {
+			item[strings.ToLower(o.method)] = o.document(schemas)
+		}
+		document.Paths[e.path] = item
+	}
+	document.Components.Schemas = schemas.Components()
+	if h.snapshots != nil && h.adminToken != "" {
+		document.Components.SecuritySchemes = map[string]openapi.SecurityScheme{
+			adminScheme: {Type: "http", Scheme: "bearer", Description: "The admin token of the server"},
+		}
+	}
+
+	// Money also decodes a bare integer as whole units of the default
+	// currency.
+	if money, ok := document.Components.Schemas["Money"]; ok {
+		object := *money
+		document.Components.Schemas["Money"] = &openapi.Schema{OneOf: []*openapi.Schema{
+			&object,
+			{Type: "integer", Description: "Whole units of " + car.DefaultCurrency},
+		}}
+	}
+	return document
+}
+
+func (o operation) document(schemas *openapi.Schemas) *openapi.Operation {
+	op := &openapi.Operation{
+		Summary:    o.summary,
+		Deprecated: o.deprecated,
+		Responses:  make(map[string]openapi.Response, len(o.responses)),
+	}
+	for _, p := range o.params {
+		schema, ok := p.schema.(*openapi.Schema)
+		if !ok {
+			schema = schemas.Of(p.schema)
+		}
+		op.Parameters = append(op.Parameters, openapi.Parameter{
+			Name: p.name, In: p.in, Description: p.description, Required: p.required, Schema: schema,
+		})
+	}
+	responses := o.responses
+	if o.body != nil {
+		op.RequestBody = &openapi.RequestBody{Required: true, Content: content(schemas, o.body)}
+		// Every body is read by readBody.
+		responses = append(failures(http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType), responses...)
+	}
+	if o.admin {
+		op.Security = []map[string][]string{{adminScheme: {}}}
+		responses = append(failures(http.StatusUnauthorized), responses...)
+	}
+	for _, r := range responses {
+		response := openapi.Response{Description: r.description}
+		if r.body != nil {
+			response.Content = content(schemas, r.body)
+		}
+		headers := r.headers
+		if o.deprecated {
+			headers = append(headers[:len(headers):len(headers)], deprecationHeaders...)
+		}
+		for _, name := range headers {
+			if response.Headers == nil {
+				response.Headers = make(map[string]openapi.Header)
+			}
+			response.Headers[name] = openapi.Header{Description: headerDescriptions[name], Schema: &openapi.Schema{Type: "string"}}
+		}
+		op.Responses[strconv.Itoa(r.status)] = response
+	}
+	return op
+}
+
+// content describes a body of the type of v, plain text for strings and
+// JSON otherwise.
+func content(schemas *openapi.Schemas, v any) map[string]openapi.MediaType {
+	if _, text := v.(string); text {
+		return map[string]openapi.MediaType{"text/plain": {Schema: &openapi.Schema{Type: "string"}}}
+	}
+	return map[string]openapi.MediaType{"application/json": {Schema: schemas.Of(v)}}
+}
+
+func (h *Handler) GETOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowedError(w, r)
+		return
+	}
+	h.writeJSON(w, r, h.OpenAPI())
+}
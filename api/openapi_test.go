@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/openapi"
+	"github.com/YoungOak/GoAPI/internal/servertest"
+	"github.com/YoungOak/GoAPI/snapshot"
+)
+
+// routeRecorder is a servertest.Router remembering the routes added to it.
+type routeRecorder struct {
+	servertest.Router
+	routes []string
+}
+
+func (r *routeRecorder) AddHandler(route string, handler http.HandlerFunc) {
+	r.routes = append(r.routes, route)
+	r.Router.AddHandler(route, handler)
+}
+
+/*
+TestOpenAPI_MatchesRoutes registers the API as the app does, every route
+included, and checks the document served by each version has exactly the
+paths under its prefix, with an operation for each method the handlers
+accept. The unversioned routes are those of every version.
+*/
+func TestOpenAPI_MatchesRoutes(t *testing.T) {
+	snapshots, _ := snapshot.NewStore(t.TempDir(), clock.System)
+	router := &routeRecorder{Router: servertest.NewRouter()}
+	newTestHandler(data.NewManager(), WithSnapshots(snapshots), WithAdminToken("secret")).Register(router)
+
+	routes := make(map[string][]string)
+	for _, route := range router.routes {
+		prefix := ""
+		for _, v := range versions {
+			if strings.HasPrefix(route, "/"+v.name+"/") {
+				prefix = "/" + v.name
+			}
+		}
+		routes[prefix] = append(routes[prefix], strings.TrimPrefix(route, prefix))
+	}
+
+	unversioned := make(map[string]bool)
+	for _, v := range versions {
+		prefix := "/" + v.name
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, prefix+"/openapi.json", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected response code %v, got %v", http.StatusOK, rr.Code)
+		}
+		var document openapi.Document
+		if err := json.Unmarshal(rr.Body.Bytes(), &document); err != nil {
+			t.Fatalf("unexpected error decoding document: %v", err)
+		}
+		if len(document.Servers) != 1 || document.Servers[0].URL != prefix {
+			t.Fatalf("%s: unexpected servers: %v", v.name, document.Servers)
+		}
+
+		documented := make([]string, 0, len(document.Paths))
+		for path := range document.Paths {
+			documented = append(documented, path)
+			unversioned[path] = true
+		}
+		sort.Strings(documented)
+		sort.Strings(routes[prefix])
+		if !reflect.DeepEqual(documented, routes[prefix]) {
+			t.Fatalf("%s: documented paths differ from routes, documented: %v, routes: %v", v.name, documented, routes[prefix])
+		}
+
+		for _, path := range routes[prefix] {
+			for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch} {
+				_, isDocumented := document.Paths[path][strings.ToLower(method)]
+				rr := httptest.NewRecorder()
+				router.ServeHTTP(rr, adminRequest(method, prefix+path, "secret", "{}"))
+				if isAllowed := rr.Code != http.StatusMethodNotAllowed; isAllowed != isDocumented {
+					t.Errorf("%s %s%s: allowed: %v, documented: %v", method, prefix, path, isAllowed, isDocumented)
+				}
+			}
+		}
+	}
+
+	if len(routes[""]) != len(unversioned) {
+		t.Fatalf("unversioned routes differ from the paths of the versions, routes: %v, paths: %v", routes[""], unversioned)
+	}
+	for _, route := range routes[""] {
+		if !unversioned[route] {
+			t.Fatalf("unversioned route %s is not a path of any version", route)
+		}
+	}
+}
+
+func TestOpenAPI_References(t *testing.T) {
+	snapshots, _ := snapshot.NewStore(t.TempDir(), clock.System)
+	for _, v := range versions {
+		t.Run(v.name, func(t *testing.T) {
+			testReferences(t, newTestHandler(data.NewManager(), WithSnapshots(snapshots), WithAdminToken("secret")).withVersion(v).OpenAPI())
+		})
+	}
+}
+
+// testReferences checks every reference of document resolves.
+func testReferences(t *testing.T, document openapi.Document) {
+	var check func(where string, schema *openapi.Schema)
+	check = func(where string, schema *openapi.Schema) {
+		if schema == nil {
+			return
+		}
+		if schema.Ref != "" && document.Resolve(schema) == schema {
+			t.Errorf("%s: unresolved reference %s", where, schema.Ref)
+		}
+		check(where, schema.Items)
+		check(where, schema.AdditionalProperties)
+		for _, property := range schema.Properties {
+			check(where, property)
+		}
+		for _, alternative := range schema.OneOf {
+			check(where, alternative)
+		}
+	}
+	for path, item := range document.Paths {
+		for method, operation := range item {
+			where := method + " " + path
+			for _, parameter := range operation.Parameters {
+				check(where, parameter.Schema)
+			}
+			if operation.RequestBody != nil {
+				for _, media := range operation.RequestBody.Content {
+					check(where, media.Schema)
+				}
+			}
+			for _, response := range operation.Responses {
+				for _, media := range response.Content {
+					check(where, media.Schema)
+				}
+			}
+		}
+	}
+	for name, schema := range document.Components.Schemas {
+		check(name, schema)
+	}
+}
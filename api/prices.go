@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/YoungOak/GoAPI/data"
+)
+
+func (h *Handler) GETCarPrices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowedError(w, r)
+		return
+	}
+
+	id := carID(r)
+
+	prices, err := h.cars.PriceHistory(r.Context(), id)
+	if err != nil {
+		if _, notFound := err.(data.ErrorRecordNotFound); notFound {
+			h.logger.WarnContext(r.Context(), err.Error())
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(err.Error()))
+		} else {
+			h.unexpectedError(w, r, "error getting car prices", err)
+		}
+		return
+	}
+
+	h.writeJSON(w, r, prices)
+}
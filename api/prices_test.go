@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+func TestGETCarPrices(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	h := newTestHandler(cars)
+	_ = cars.Add(ctx, testRecord)
+	discounted := testRecord
+	discounted.Price = car.NewMoney(9000, "USD")
+	_ = cars.Update(ctx, discounted)
+
+	tests := []struct {
+		name       string
+		target     string
+		wantCode   int
+		wantPrices []car.Money
+	}{
+		{"price history", "/car/prices?id=123", http.StatusOK, []car.Money{testRecord.Price, discounted.Price}},
+		{"unknown car", "/car/prices?id=456", http.StatusNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.target, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(h.GETCarPrices)
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("Expected response code %v, got %v", tt.wantCode, rr.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var history []car.PriceChange
+			if err := json.Unmarshal(rr.Body.Bytes(), &history); err != nil {
+				t.Fatalf("Failed unmarshalling response: %v", err)
+			}
+			if len(history) != len(tt.wantPrices) {
+				t.Fatalf("Unexpected price history: %v", history)
+			}
+			for i, change := range history {
+				if change.Price != tt.wantPrices[i] {
+					t.Fatalf("Unexpected price %d, wanted: %v, got: %v", i, tt.wantPrices[i], change.Price)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+type ErrorInvalidParameter struct {
+	Name  string
+	Value string
+}
+
+func (e ErrorInvalidParameter) Error() string {
+	return fmt.Sprintf("query parameter '%s' invalid value: '%s'", e.Name, e.Value)
+}
+
+// parseCarsQuery builds a data.Query from the query string of GET /cars.
+func parseCarsQuery(values url.Values) (data.Query, error) {
+	q := data.Query{
+		Make:     values.Get("make"),
+		Model:    values.Get("model"),
+		Category: values.Get("category"),
+		Color:    values.Get("color"),
+		VIN:      strings.ToUpper(values.Get("vin")),
+		Currency: strings.ToUpper(values.Get("currency")),
+		Status:   car.Status(values.Get("status")),
+	}
+
+	var err error
+	for _, r := range []struct {
+		param string
+		bound **int
+	}{
+		{"min_year", &q.Year.Min},
+		{"max_year", &q.Year.Max},
+		{"min_mileage", &q.Mileage.Min},
+		{"max_mileage", &q.Mileage.Max},
+	} {
+		if *r.bound, err = parseOptionalInt(values, r.param); err != nil {
+			return data.Query{}, err
+		}
+	}
+
+	// Price bounds are whole units of the currency filtered on, amounts
+	// in different currencies do not compare.
+	if values.Get("min_price") != "" || values.Get("max_price") != "" {
+		if q.Currency == "" {
+			return data.Query{}, data.ErrorCurrencyMissing{}
+		}
+	}
+	if q.Price.Min, err = parseOptionalUnits(values, "min_price", q.Currency); err != nil {
+		return data.Query{}, err
+	}
+	if q.Price.Max, err = parseOptionalUnits(values, "max_price", q.Currency); err != nil {
+		return data.Query{}, err
+	}
+
+	switch sort := values.Get("sort"); sort {
+	case "":
+	case string(data.SortByID), string(data.SortByYear), string(data.SortByPrice), string(data.SortByMileage):
+		q.SortBy = data.SortField(sort)
+	default:
+		return data.Query{}, ErrorInvalidParameter{"sort", sort}
+	}
+
+	switch order := values.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		return data.Query{}, ErrorInvalidParameter{"order", order}
+	}
+
+	if q.PriceDroppedSince, err = parseOptionalTime(values, "price_dropped_since"); err != nil {
+		return data.Query{}, err
+	}
+
+	if q.Offset, err = parseNonNegativeInt(values, "offset"); err != nil {
+		return data.Query{}, err
+	}
+	if q.Limit, err = parseNonNegativeInt(values, "limit"); err != nil {
+		return data.Query{}, err
+	}
+
+	return q, nil
+}
+
+/*
+requestedUnit returns the odometer unit asked for by the units query
+parameter or, failing that, the Accept-Units header. It is empty when the
+client did not ask for one.
+*/
+func requestedUnit(r *http.Request) (car.DistanceUnit, error) {
+	name, raw := "units", r.URL.Query().Get("units")
+	if raw == "" {
+		name, raw = "Accept-Units", r.Header.Get("Accept-Units")
+	}
+	if raw == "" {
+		return "", nil
+	}
+
+	unit := car.DistanceUnit(strings.ToLower(strings.TrimSpace(raw)))
+	if !unit.Valid() {
+		return "", ErrorInvalidParameter{name, raw}
+	}
+	return unit, nil
+}
+
+func parseOptionalInt(values url.Values, name string) (*int, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, ErrorInvalidParameter{name, raw}
+	}
+	return &n, nil
+}
+
+// parseOptionalUnits reads an amount of whole units of currency, such as
+// 199.99, as minor units.
+func parseOptionalUnits(values url.Values, name, currency string) (*int, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	money, err := car.ParseUnits(raw, currency)
+	if err != nil {
+		return nil, ErrorInvalidParameter{name, raw}
+	}
+	return &money.Amount, nil
+}
+
+// parseOptionalTime reads an RFC 3339 timestamp or a date, taken as
+// midnight UTC. The zero time means the parameter is absent.
+func parseOptionalTime(values url.Values, name string) (time.Time, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrorInvalidParameter{name, raw}
+}
+
+func parseNonNegativeInt(values url.Values, name string) (int, error) {
+	n, err := parseOptionalInt(values, name)
+	if err != nil || n == nil {
+		return 0, err
+	}
+	if *n < 0 {
+		return 0, ErrorInvalidParameter{name, values.Get(name)}
+	}
+	return *n, nil
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/YoungOak/GoAPI/data"
+)
+
+func TestParseCarsQuery(t *testing.T) {
+	year := 2015
+	// Price bounds are whole units, 20000 USD is 2000000 cents.
+	price := 2000000
+	yen := 150
+
+	tests := []struct {
+		name      string
+		rawQuery  string
+		wantQuery data.Query
+		wantErr   error
+	}{
+		{
+			name:      "empty query",
+			rawQuery:  "",
+			wantQuery: data.Query{},
+		},
+		{
+			name:     "filters and pagination",
+			rawQuery: "make=Toyota&color=Blue&vin=1hgcm82633a004352&min_year=2015&currency=usd&max_price=20000&sort=price&order=desc&offset=10&limit=5",
+			wantQuery: data.Query{
+				Make:     "Toyota",
+				Color:    "Blue",
+				VIN:      "1HGCM82633A004352",
+				Currency: "USD",
+				Year:     data.Range{Min: &year},
+				Price:    data.Range{Max: &price},
+				SortBy:   data.SortByPrice,
+				Desc:     true,
+				Offset:   10,
+				Limit:    5,
+			},
+		},
+		{
+			name:     "invalid bound",
+			rawQuery: "min_year=new",
+			wantErr:  ErrorInvalidParameter{"min_year", "new"},
+		},
+		{
+			name:     "price bound in whole units of the currency",
+			rawQuery: "currency=jpy&min_price=150",
+			wantQuery: data.Query{
+				Currency: "JPY",
+				Price:    data.Range{Min: &yen},
+			},
+		},
+		{
+			name:     "price bound finer than the currency",
+			rawQuery: "currency=usd&max_price=19.999",
+			wantErr:  ErrorInvalidParameter{"max_price", "19.999"},
+		},
+		{
+			name:     "price bound without currency",
+			rawQuery: "max_price=20000",
+			wantErr:  data.ErrorCurrencyMissing{},
+		},
+		{
+			name:      "price dropped since date",
+			rawQuery:  "price_dropped_since=2024-03-01",
+			wantQuery: data.Query{PriceDroppedSince: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:      "price dropped since timestamp",
+			rawQuery:  "price_dropped_since=2024-03-01T10:30:00Z",
+			wantQuery: data.Query{PriceDroppedSince: time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+		},
+		{
+			name:     "invalid price dropped since",
+			rawQuery: "price_dropped_since=yesterday",
+			wantErr:  ErrorInvalidParameter{"price_dropped_since", "yesterday"},
+		},
+		{
+			name:     "invalid sort",
+			rawQuery: "sort=color",
+			wantErr:  ErrorInvalidParameter{"sort", "color"},
+		},
+		{
+			name:     "invalid order",
+			rawQuery: "order=up",
+			wantErr:  ErrorInvalidParameter{"order", "up"},
+		},
+		{
+			name:     "negative limit",
+			rawQuery: "limit=-1",
+			wantErr:  ErrorInvalidParameter{"limit", "-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.rawQuery)
+			gotQuery, err := parseCarsQuery(values)
+			if err != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("unexpected error, wanted success, got: %v", err)
+				}
+				if err.Error() != tt.wantErr.Error() {
+					t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+				}
+				return
+			} else if tt.wantErr != nil {
+				t.Fatalf("unexpected success, expected error: %s", tt.wantErr.Error())
+			}
+			if !reflect.DeepEqual(gotQuery, tt.wantQuery) {
+				t.Fatalf("unexpected query, wanted: %+v, got: %+v", tt.wantQuery, gotQuery)
+			}
+		})
+	}
+}
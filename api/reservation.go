@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/reservation"
+)
+
+type reservationRequest struct {
+	Holder string `json:"holder"`
+	// Duration such as "48h", the configured hold when empty.
+	Duration string `json:"duration,omitempty"`
+}
+
+func (h *Handler) reservationsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		list, err := h.reservations.List(r.Context())
+		if err != nil {
+			h.reservationError(w, r, err)
+			return
+		}
+		h.writeJSON(w, r, list)
+	default:
+		methodNotAllowedError(w, r)
+	}
+}
+
+func (h *Handler) reservationHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.POSTReservation(w, r)
+	case http.MethodGet:
+		h.GETReservation(w, r)
+	case http.MethodDelete:
+		h.DELETEReservation(w, r)
+	default:
+		methodNotAllowedError(w, r)
+	}
+}
+
+func (h *Handler) POSTReservation(w http.ResponseWriter, r *http.Request) {
+	var request reservationRequest
+	if !h.decodeBody(w, r, &request) {
+		return
+	}
+
+	var err error
+	hold := h.reservationHold
+	if request.Duration != "" {
+		hold, err = time.ParseDuration(request.Duration)
+		if err != nil {
+			h.logger.WarnContext(r.Context(), fmt.Sprintf("invalid reservation duration: %s", err.Error()))
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("invalid reservation duration: '%s'", request.Duration)))
+			return
+		}
+	}
+
+	id := carID(r)
+
+	reserved, err := h.reservations.Reserve(r.Context(), id, request.Holder, hold)
+	if err != nil {
+		h.reservationError(w, r, err)
+		return
+	}
+
+	jsonReservation, err := json.Marshal(reserved)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), fmt.Sprintf("error marshalling reservation: %s", err.Error()))
+		internalServerError(w, r)
+		return
+	}
+
+	h.logger.Info(fmt.Sprintf("car with id: '%s' reserved by '%s' until %s", id, reserved.Holder, reserved.Until.Format(time.RFC3339)))
+	w.Header().Set("Content-Type", h.contentType(r))
+	w.WriteHeader(http.StatusCreated)
+	w.Write(jsonReservation)
+}
+
+func (h *Handler) GETReservation(w http.ResponseWriter, r *http.Request) {
+	reserved, err := h.reservations.Get(r.Context(), carID(r))
+	if err != nil {
+		h.reservationError(w, r, err)
+		return
+	}
+	h.writeJSON(w, r, reserved)
+}
+
+func (h *Handler) DELETEReservation(w http.ResponseWriter, r *http.Request) {
+	id := carID(r)
+
+	if err := h.reservations.Release(r.Context(), id); err != nil {
+		h.reservationError(w, r, err)
+		return
+	}
+
+	h.logger.Info(fmt.Sprintf("released reservation of car with id: '%s'", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) logExpiredReservation(expired reservation.Reservation) {
+	h.logger.Info(fmt.Sprintf("reservation of car with id: '%s' by '%s' expired", expired.CarID, expired.Holder))
+}
+
+func (h *Handler) reservationError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err.(type) {
+	case reservation.ErrorHolderMissing, reservation.ErrorInvalidDuration:
+		h.logger.WarnContext(r.Context(), err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+	case reservation.ErrorNotReserved, data.ErrorRecordNotFound:
+		h.logger.WarnContext(r.Context(), err.Error())
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(err.Error()))
+	case reservation.ErrorAlreadyReserved, data.ErrorIllegalTransition:
+		h.logger.WarnContext(r.Context(), err.Error())
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(err.Error()))
+	default:
+		h.unexpectedError(w, r, "error handling reservation", err)
+	}
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/reservation"
+)
+
+func TestReservationHandler(t *testing.T) {
+	ctx := context.Background()
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	cars := data.NewManager(data.WithClock(fake))
+	h := NewHandler(cars, slog.Default(), fake)
+	_ = cars.Add(ctx, testRecord)
+
+	tests := []struct {
+		name      string
+		method    string
+		target    string
+		body      string
+		wantCode  int
+		wantUntil time.Time
+	}{
+		{"reserve", http.MethodPost, "/reservation?id=123", `{"holder": "Alice"}`, http.StatusCreated, fake.Now().Add(defaultReservationHold)},
+		{"get", http.MethodGet, "/reservation?id=123", "", http.StatusOK, fake.Now().Add(defaultReservationHold)},
+		{"conflict", http.MethodPost, "/reservation?id=123", `{"holder": "Bob"}`, http.StatusConflict, time.Time{}},
+		{"extend", http.MethodPost, "/reservation?id=123", `{"holder": "Alice", "duration": "72h"}`, http.StatusCreated, fake.Now().Add(72 * time.Hour)},
+		{"invalid duration", http.MethodPost, "/reservation?id=123", `{"holder": "Alice", "duration": "soon"}`, http.StatusBadRequest, time.Time{}},
+		{"missing holder", http.MethodPost, "/reservation?id=123", `{}`, http.StatusBadRequest, time.Time{}},
+		{"unknown car", http.MethodPost, "/reservation?id=456", `{"holder": "Bob"}`, http.StatusNotFound, time.Time{}},
+		{"release", http.MethodDelete, "/reservation?id=123", "", http.StatusNoContent, time.Time{}},
+		{"released", http.MethodGet, "/reservation?id=123", "", http.StatusNotFound, time.Time{}},
+		{"release twice", http.MethodDelete, "/reservation?id=123", "", http.StatusNotFound, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.target, bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(h.reservationHandler)
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("Expected response code %v, got %v: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+			if tt.wantUntil.IsZero() {
+				return
+			}
+
+			var got reservation.Reservation
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Failed unmarshalling response: %v", err)
+			}
+			if got.Holder != "Alice" || !got.Until.Equal(tt.wantUntil) {
+				t.Fatalf("Unexpected reservation: %v", got)
+			}
+		})
+	}
+}
+
+func TestReservationsHandler(t *testing.T) {
+	ctx := context.Background()
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	cars := data.NewManager(data.WithClock(fake))
+	h := NewHandler(cars, slog.Default(), fake)
+	_ = cars.Add(ctx, testRecord)
+	_, _ = h.reservations.Reserve(ctx, testRecord.ID, "Alice", time.Hour)
+
+	req, err := http.NewRequest(http.MethodGet, "/reservations", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(h.reservationsHandler)
+	handler.ServeHTTP(rr, req)
+
+	var got []reservation.Reservation
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Failed unmarshalling response: %v", err)
+	}
+	if len(got) != 1 || got[0].CarID != testRecord.ID {
+		t.Fatalf("Unexpected reservations: %v", got)
+	}
+}
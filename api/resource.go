@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/server"
+)
+
+type ErrorIDMismatch struct {
+	Path string
+	Body string
+}
+
+func (e ErrorIDMismatch) Error() string {
+	return fmt.Sprintf("id '%s' of the body does not match id '%s' of the path", e.Body, e.Path)
+}
+
+// reservedIDs are the first segments of the static routes under /cars/,
+// which take precedence over /cars/{id}.
+var reservedIDs = []string{"trash", "batch"}
+
+type ErrorReservedID struct {
+	ID string
+}
+
+func (e ErrorReservedID) Error() string {
+	return fmt.Sprintf("id '%s' is reserved for the route /cars/%s", e.ID, e.ID)
+}
+
+// CheckID refuses the IDs of cars that /cars/{id} could not reach, as
+// another route takes the path.
+func CheckID(id string) error {
+	if slices.Contains(reservedIDs, id) {
+		return ErrorReservedID{id}
+	}
+	return nil
+}
+
+// carID returns the ID of the car a request is about, from the path of
+// the /cars/{id} routes or from the id query parameter of the deprecated
+// /car routes.
+func carID(r *http.Request) string {
+	if id := server.PathValue(r, "id"); id != "" {
+		return id
+	}
+	return r.URL.Query().Get("id")
+}
+
+// bindID sets the ID of a record decoded from the body to the one of the
+// path, when there is one. A record may leave its ID out but not
+// contradict the path.
+func bindID(r *http.Request, record *car.Record) error {
+	id := server.PathValue(r, "id")
+	if id == "" {
+		return nil
+	}
+	if record.ID != "" && record.ID != id {
+		return ErrorIDMismatch{id, record.ID}
+	}
+	record.ID = id
+	return nil
+}
+
+func (h *Handler) carResourceHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GETCar(w, r)
+	case http.MethodPut:
+		h.PUTCar(w, r)
+	case http.MethodPatch:
+		h.PATCHCar(w, r)
+	case http.MethodDelete:
+		h.DELETECar(w, r)
+	default:
+		methodNotAllowedError(w, r)
+	}
+}
+
+/*
+PATCHCar changes the fields of a car given in the body, a JSON merge patch
+as RFC 7396 defines: members set to null are removed, objects such as the
+price are merged and other values replace the current ones. The patched
+car is then checked and stored as PUTCar does, in a single Patch of the
+store so concurrent patches of other fields are not lost.
+*/
+func (h *Handler) PATCHCar(w http.ResponseWriter, r *http.Request) {
+	var patch map[string]any
+	if !h.decodeBody(w, r, &patch) {
+		return
+	}
+	if patch == nil {
+		h.bodyError(w, r, ErrorInvalidBody{"expected a JSON object"})
+		return
+	}
+
+	id := carID(r)
+	// invalid is set when the patched car cannot be read in the shape of
+	// the version, a fault of the body.
+	var invalid error
+	_, err := h.cars.Patch(r.Context(), id, func(current car.Record) (car.Record, error) {
+		body, err := h.patched(current, patch)
+		if err != nil {
+			return car.Record{}, err
+		}
+		record, err := h.version.fromRequest(body)
+		if err != nil {
+			invalid = err
+			return car.Record{}, err
+		}
+		return record, bindID(r, &record)
+	})
+	if invalid != nil {
+		h.bodyError(w, r, invalid)
+		return
+	}
+	h.updated(w, r, id, err)
+}
+
+// patched returns record as JSON in the shape of the version, with patch
+// merged into it.
+func (h *Handler) patched(record car.Record, patch map[string]any) ([]byte, error) {
+	current, err := json.Marshal(h.record(record))
+	if err != nil {
+		return nil, err
+	}
+	var document map[string]any
+	if err := json.Unmarshal(current, &document); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(document, patch))
+}
+
+// mergePatch applies a JSON merge patch to document, both decoded from
+// JSON, and returns it.
+func mergePatch(document, patch map[string]any) map[string]any {
+	for name, value := range patch {
+		switch value := value.(type) {
+		case nil:
+			delete(document, name)
+		case map[string]any:
+			target, _ := document[name].(map[string]any)
+			if target == nil {
+				target = make(map[string]any)
+			}
+			document[name] = mergePatch(target, value)
+		default:
+			document[name] = value
+		}
+	}
+	return document
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/servertest"
+)
+
+func TestCarResource(t *testing.T) {
+	valid := `{"make": "Toyota", "model": "Camry", "category": "Sedan", "package": "Standard",
+		"color": "Blue", "year": 2020, "mileage": 1000, "price": 20000}`
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		wantCode       int
+		wantBody       string
+		wantDeprecated bool
+	}{
+		{"add", http.MethodPost, "/cars", strings.Replace(valid, "{", `{"id": "123",`, 1), http.StatusCreated, `"id":"123"`, false},
+		{"get", http.MethodGet, "/cars/123", "", http.StatusOK, `"color":"Blue"`, false},
+		{"get unknown", http.MethodGet, "/cars/456", "", http.StatusNotFound, "", false},
+		{"add reserved id", http.MethodPost, "/cars", strings.Replace(valid, "{", `{"id": "trash",`, 1), http.StatusBadRequest, "reserved", false},
+		{"add reserved id in batch", http.MethodPost, "/cars/batch", `{"operations": [{"op": "add", "car": ` + strings.Replace(valid, "{", `{"id": "batch",`, 1) + `}]}`, http.StatusBadRequest, "reserved", false},
+		{"replace without id", http.MethodPut, "/cars/123", strings.Replace(valid, "Blue", "Green", 1), http.StatusAccepted, "", false},
+		{"replace other id", http.MethodPut, "/cars/123", strings.Replace(valid, "{", `{"id": "456",`, 1), http.StatusBadRequest, "", false},
+		{"patch", http.MethodPatch, "/cars/123", `{"color": "Red", "price": 15000.5}`, http.StatusAccepted, "", false},
+		{"patched", http.MethodGet, "/cars/123", "", http.StatusOK, `"color":"Red","year":2020,"mileage":1000,"price":15000.5,"currency":"USD"`, false},
+		{"patch price object", http.MethodPatch, "/cars/123", `{"price": {"amount": 1500000}}`, http.StatusAccepted, "", false},
+		{"patched price", http.MethodGet, "/v2/cars/123", "", http.StatusOK, `"price":{"amount":1500000,"currency":"USD"}`, false},
+		{"patch price finer than the currency", http.MethodPatch, "/cars/123", `{"price": 15000.505}`, http.StatusBadRequest, "", false},
+		{"patch unknown field", http.MethodPatch, "/cars/123", `{"colour": "Red"}`, http.StatusBadRequest, "", false},
+		{"patch id", http.MethodPatch, "/cars/123", `{"id": "456"}`, http.StatusBadRequest, "", false},
+		{"patch required field away", http.MethodPatch, "/cars/123", `{"make": null}`, http.StatusBadRequest, "", false},
+		{"patch unknown car", http.MethodPatch, "/cars/456", `{"color": "Red"}`, http.StatusNotFound, "", false},
+		{"reserve", http.MethodPost, "/cars/123/reserve", "", http.StatusOK, `"status":"reserved"`, false},
+		{"history", http.MethodGet, "/cars/123/history", "", http.StatusOK, `"to":"reserved"`, false},
+		{"deprecated get", http.MethodGet, "/car?id=123", "", http.StatusOK, `"status":"reserved"`, true},
+		{"deprecated release", http.MethodPost, "/car/release?id=123", "", http.StatusOK, `"status":"available"`, true},
+		{"delete", http.MethodDelete, "/cars/123", "", http.StatusNoContent, "", false},
+		{"restore", http.MethodPost, "/cars/trash/123/restore", "", http.StatusOK, `"id":"123"`, false},
+		{"method not allowed", http.MethodPost, "/cars/123", "", http.StatusMethodNotAllowed, "", false},
+	}
+
+	for _, mode := range []struct {
+		name string
+		opts []Option
+	}{
+		{"without validation", nil},
+		{"with validation", []Option{WithResponseValidation()}},
+	} {
+		t.Run(mode.name, func(t *testing.T) {
+			router := servertest.NewRouter()
+			newTestHandler(data.NewManager(), mode.opts...).Register(router)
+
+			for _, tt := range tests {
+				rr := httptest.NewRecorder()
+				router.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+				if rr.Code != tt.wantCode || !strings.Contains(rr.Body.String(), tt.wantBody) {
+					t.Fatalf("%s: expected %v %q, got %v %q", tt.name, tt.wantCode, tt.wantBody, rr.Code, rr.Body.String())
+				}
+				if deprecated := rr.Header().Get("Deprecation") != "" && rr.Header().Get("Sunset") != ""; deprecated != tt.wantDeprecated {
+					t.Fatalf("%s: expected deprecated %v, got headers %v", tt.name, tt.wantDeprecated, rr.Header())
+				}
+			}
+		})
+	}
+}
+
+func TestCheckID_Routes(t *testing.T) {
+	for _, e := range newTestHandler(data.NewManager()).endpoints() {
+		rest, ok := strings.CutPrefix(e.path, "/cars/")
+		if segment, _, _ := strings.Cut(rest, "/"); ok && segment != "{id}" {
+			if err := CheckID(segment); err == nil {
+				t.Errorf("expected id '%s' of route %s to be reserved", segment, e.path)
+			}
+		}
+	}
+	if err := CheckID("123"); err != nil {
+		t.Fatalf("unexpected error checking id: %v", err)
+	}
+}
+
+// slowReads is a data.Manager taking its time to get a car, leaving room
+// for other requests to change it meanwhile.
+type slowReads struct {
+	data.Manager
+}
+
+func (m slowReads) Get(ctx context.Context, id string) (car.Record, error) {
+	record, err := m.Manager.Get(ctx, id)
+	time.Sleep(time.Millisecond)
+	return record, err
+}
+
+func TestPATCHCar_Concurrent(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	router := servertest.NewRouter()
+	newTestHandler(slowReads{cars}).Register(router)
+	_ = cars.Add(ctx, testRecord)
+
+	// Patches of different fields racing each other are all kept.
+	for round := 0; round < 5; round++ {
+		patches := []string{
+			fmt.Sprintf(`{"color": "Color %d"}`, round),
+			fmt.Sprintf(`{"package": "Package %d"}`, round),
+			fmt.Sprintf(`{"mileage": %d}`, 2000+round),
+			fmt.Sprintf(`{"year": %d}`, 2000+round),
+		}
+		var wg sync.WaitGroup
+		for _, patch := range patches {
+			wg.Add(1)
+			go func(patch string) {
+				defer wg.Done()
+				rr := httptest.NewRecorder()
+				router.ServeHTTP(rr, httptest.NewRequest(http.MethodPatch, "/cars/123", strings.NewReader(patch)))
+				if rr.Code != http.StatusAccepted {
+					t.Errorf("%s: expected %v, got %v %q", patch, http.StatusAccepted, rr.Code, rr.Body.String())
+				}
+			}(patch)
+		}
+		wg.Wait()
+
+		record, _ := cars.Get(ctx, "123")
+		if record.Color != fmt.Sprintf("Color %d", round) || record.Package != fmt.Sprintf("Package %d", round) ||
+			record.Mileage != 2000+round || record.Year != 2000+round {
+			t.Fatalf("round %d: expected every patch to be kept, got: %+v", round, record)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/snapshot"
+)
+
+func (h *Handler) snapshotsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GETSnapshots(w, r)
+	case http.MethodPost:
+		h.POSTSnapshot(w, r)
+	default:
+		methodNotAllowedError(w, r)
+	}
+}
+
+func (h *Handler) GETSnapshots(w http.ResponseWriter, r *http.Request) {
+	list, err := h.snapshots.List()
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), fmt.Sprintf("error listing snapshots: %s", err.Error()))
+		internalServerError(w, r)
+		return
+	}
+	h.writeJSON(w, r, list)
+}
+
+func (h *Handler) POSTSnapshot(w http.ResponseWriter, r *http.Request) {
+	info, err := h.SaveSnapshot(r.Context())
+	if err != nil {
+		h.unexpectedError(w, r, "error saving snapshot", err)
+		return
+	}
+
+	jsonInfo, err := json.Marshal(info)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), fmt.Sprintf("error marshalling snapshot: %s", err.Error()))
+		internalServerError(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", h.contentType(r))
+	w.WriteHeader(http.StatusCreated)
+	w.Write(jsonInfo)
+}
+
+func (h *Handler) POSTSnapshotRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowedError(w, r)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+
+	err := h.RestoreSnapshot(r.Context(), name)
+	if err != nil {
+		switch err.(type) {
+		case snapshot.ErrorSnapshotNotFound:
+			h.logger.WarnContext(r.Context(), err.Error())
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(err.Error()))
+		case snapshot.ErrorUnsupportedVersion, snapshot.ErrorCorruptSnapshot,
+			data.ErrorAlreadyExists, data.ErrorVINAlreadyExists:
+			h.logger.WarnContext(r.Context(), err.Error())
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(err.Error()))
+		default:
+			h.unexpectedError(w, r, "error restoring snapshot", err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(fmt.Sprintf("restored snapshot '%s'", name)))
+}
+
+// SaveSnapshot saves the cars of the Handler to a new snapshot.
+func (h *Handler) SaveSnapshot(ctx context.Context) (snapshot.Info, error) {
+	state, err := h.cars.Dump(ctx)
+	if err != nil {
+		return snapshot.Info{}, err
+	}
+	info, err := h.snapshots.Save(state)
+	if err != nil {
+		return snapshot.Info{}, err
+	}
+	h.logger.Info(fmt.Sprintf("saved snapshot: '%s'", info.Name))
+	return info, nil
+}
+
+// SaveSnapshots saves a snapshot every interval until ctx is done.
+func (h *Handler) SaveSnapshots(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := h.SaveSnapshot(ctx); err != nil && ctx.Err() == nil {
+				h.logger.ErrorContext(ctx, fmt.Sprintf("error saving snapshot: %s", err.Error()))
+			}
+		}
+	}
+}
+
+/*
+RestoreSnapshot replaces the cars of the Handler with a snapshot. The
+responses stored for Idempotency-Key are dropped, as they describe cars
+that may be gone, and the reservations too: the cars reserved in the
+snapshot are held on its behalf for the reservation hold, so they become
+available again unless reserved anew.
+*/
+func (h *Handler) RestoreSnapshot(ctx context.Context, name string) error {
+	state, err := h.snapshots.Load(name)
+	if err != nil {
+		return err
+	}
+	if err := h.cars.Load(ctx, state); err != nil {
+		return err
+	}
+	h.idempotency.Clear()
+	// The holds are reset even when ctx ends now, to match the cars.
+	held, err := h.reservations.Reset(context.WithoutCancel(ctx), fmt.Sprintf("snapshot '%s'", name), h.reservationHold)
+	if err != nil {
+		return err
+	}
+	h.logger.Info(fmt.Sprintf("restored snapshot: '%s' with %d cars, %d of them reserved", name, len(state.Records), len(held)))
+	return nil
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/reservation"
+	"github.com/YoungOak/GoAPI/internal/servertest"
+	"github.com/YoungOak/GoAPI/snapshot"
+)
+
+// adminRequest returns a request sending token as the admin token, none
+// when empty.
+func adminRequest(method, target, token, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func TestSnapshotHandlers(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	snapshots, _ := snapshot.NewStore(t.TempDir(), clock.System)
+	router := servertest.NewRouter()
+	newTestHandler(cars, WithSnapshots(snapshots), WithAdminToken("secret")).Register(router)
+	_ = cars.Add(ctx, testRecord)
+
+	serve := func(method, target, body string, header ...string) *httptest.ResponseRecorder {
+		req := adminRequest(method, target, "secret", body)
+		for i := 0; i < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := serve(http.MethodPost, "/cars/123/reservation", `{"holder": "Alice"}`); rr.Code != http.StatusCreated {
+		t.Fatalf("Expected response code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	rr := serve(http.MethodPost, "/admin/snapshots", "")
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected response code %v, got %v", http.StatusCreated, rr.Code)
+	}
+	var info snapshot.Info
+	if err := json.Unmarshal(rr.Body.Bytes(), &info); err != nil {
+		t.Fatalf("Failed unmarshalling response: %v", err)
+	}
+
+	rr = serve(http.MethodGet, "/admin/snapshots", "")
+	var list []snapshot.Info
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Failed unmarshalling response: %v", err)
+	}
+	if len(list) != 1 || list[0].Name != info.Name {
+		t.Fatalf("Unexpected snapshots: %v", list)
+	}
+
+	// Changes after the snapshot: the reservation is released, the car
+	// deleted and another one added under an Idempotency-Key.
+	added := `{"id": "456", "make": "Toyota", "model": "Camry", "category": "Sedan", "package": "Standard",
+		"color": "Blue", "year": 2020, "mileage": 1000, "price": 20000}`
+	_ = serve(http.MethodDelete, "/cars/123/reservation", "")
+	_ = cars.Delete(ctx, testRecord.ID)
+	if rr := serve(http.MethodPost, "/cars", added, "Idempotency-Key", "add-456"); rr.Code != http.StatusCreated {
+		t.Fatalf("Expected response code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	if rr := serve(http.MethodPost, "/admin/snapshots/restore?name="+info.Name, ""); rr.Code != http.StatusOK {
+		t.Fatalf("Expected response code %v, got %v: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if _, err := cars.Get(ctx, testRecord.ID); err != nil {
+		t.Fatalf("Expected deleted car to be restored, got: %v", err)
+	}
+
+	// The car reserved in the snapshot is held until the hold expires.
+	rr = serve(http.MethodGet, "/cars/123/reservation", "")
+	var held reservation.Reservation
+	if err := json.Unmarshal(rr.Body.Bytes(), &held); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected the restored car to be held, got %v: %s", rr.Code, rr.Body.String())
+	}
+	if held.Holder != "snapshot '"+info.Name+"'" {
+		t.Fatalf("Unexpected holder: %s", held.Holder)
+	}
+
+	// The response stored for the key is not replayed for a car that is
+	// gone.
+	if rr := serve(http.MethodPost, "/cars", added, "Idempotency-Key", "add-456"); rr.Code != http.StatusCreated || rr.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("Expected the car to be added again, got %v %v", rr.Code, rr.Header())
+	}
+	if _, err := cars.Get(ctx, "456"); err != nil {
+		t.Fatalf("Expected the car to be added again, got: %v", err)
+	}
+
+	if rr := serve(http.MethodPost, "/admin/snapshots/restore?name=missing", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("Expected response code %v, got %v", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestSnapshotHandlers_AdminToken(t *testing.T) {
+	snapshots, _ := snapshot.NewStore(t.TempDir(), clock.System)
+	tests := []struct {
+		name     string
+		opts     []Option
+		target   string
+		header   string
+		wantCode int
+	}{
+		{"token", []Option{WithAdminToken("secret")}, "/admin/snapshots", "Bearer secret", http.StatusOK},
+		{"versioned", []Option{WithAdminToken("secret")}, "/v2/admin/snapshots", "Bearer secret", http.StatusOK},
+		{"no token", []Option{WithAdminToken("secret")}, "/admin/snapshots", "", http.StatusUnauthorized},
+		{"versioned no token", []Option{WithAdminToken("secret")}, "/v1/admin/snapshots", "", http.StatusUnauthorized},
+		{"wrong token", []Option{WithAdminToken("secret")}, "/admin/snapshots", "Bearer secrets", http.StatusUnauthorized},
+		{"other scheme", []Option{WithAdminToken("secret")}, "/admin/snapshots", "Basic secret", http.StatusUnauthorized},
+		{"no admin token", nil, "/admin/snapshots", "Bearer ", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := servertest.NewRouter()
+			opts := append([]Option{WithSnapshots(snapshots), WithResponseValidation()}, tt.opts...)
+			newTestHandler(data.NewManager(), opts...).Register(router)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("Expected response code %v, got %v: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+			if tt.wantCode == http.StatusUnauthorized && rr.Header().Get("WWW-Authenticate") == "" {
+				t.Fatal("Expected a WWW-Authenticate header")
+			}
+		})
+	}
+}
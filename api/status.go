@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+// transitionHandler moves the car given by its ID to status.
+func (h *Handler) transitionHandler(to car.Status) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			methodNotAllowedError(w, r)
+			return
+		}
+
+		id := carID(r)
+
+		record, err := h.cars.Transition(r.Context(), id, to)
+		if err != nil {
+			_, notFound := err.(data.ErrorRecordNotFound)
+			_, illegal := err.(data.ErrorIllegalTransition)
+			if notFound {
+				h.logger.WarnContext(r.Context(), err.Error())
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(err.Error()))
+			} else if illegal {
+				h.logger.WarnContext(r.Context(), err.Error())
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(err.Error()))
+			} else {
+				h.unexpectedError(w, r, "error changing car status", err)
+			}
+			return
+		}
+
+		h.logger.Info(fmt.Sprintf("car with id: '%s' is now %s", id, to))
+		h.writeJSON(w, r, h.record(record))
+	}
+}
+
+func (h *Handler) GETCarHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowedError(w, r)
+		return
+	}
+
+	id := carID(r)
+
+	history, err := h.cars.StatusHistory(r.Context(), id)
+	if err != nil {
+		if _, notFound := err.(data.ErrorRecordNotFound); notFound {
+			h.logger.WarnContext(r.Context(), err.Error())
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(err.Error()))
+		} else {
+			h.unexpectedError(w, r, "error getting car history", err)
+		}
+		return
+	}
+
+	h.writeJSON(w, r, history)
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), fmt.Sprintf("error marshalling response: %s", err.Error()))
+		internalServerError(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", h.contentType(r))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
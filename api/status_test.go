@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+func TestTransitionHandler(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	h := newTestHandler(cars)
+	_ = cars.Add(ctx, testRecord)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		to         car.Status
+		wantCode   int
+		wantStatus car.Status
+	}{
+		{"reserve", http.MethodPost, "/car/reserve?id=123", car.StatusReserved, http.StatusOK, car.StatusReserved},
+		{"illegal transition", http.MethodPost, "/car/service?id=123", car.StatusInService, http.StatusConflict, ""},
+		{"sell", http.MethodPost, "/car/sell?id=123", car.StatusSold, http.StatusOK, car.StatusSold},
+		{"unknown car", http.MethodPost, "/car/reserve?id=456", car.StatusReserved, http.StatusNotFound, ""},
+		{"wrong method", http.MethodGet, "/car/reserve?id=123", car.StatusReserved, http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.target, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			h.transitionHandler(tt.to).ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("Expected response code %v, got %v", tt.wantCode, rr.Code)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var gotRecord car.Record
+			if err := json.Unmarshal(rr.Body.Bytes(), &gotRecord); err != nil {
+				t.Fatalf("Failed unmarshalling response: %v", err)
+			}
+			if gotRecord.Status != tt.wantStatus {
+				t.Fatalf("Unexpected status, wanted: %v, got: %v", tt.wantStatus, gotRecord.Status)
+			}
+		})
+	}
+}
+
+func TestGETCarHistory(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	h := newTestHandler(cars)
+	_ = cars.Add(ctx, testRecord)
+	_, _ = cars.Transition(ctx, testRecord.ID, car.StatusReserved)
+
+	req, err := http.NewRequest(http.MethodGet, "/car/history?id=123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(h.GETCarHistory)
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected response code %v, got %v", http.StatusOK, rr.Code)
+	}
+
+	var history []car.StatusChange
+	if err := json.Unmarshal(rr.Body.Bytes(), &history); err != nil {
+		t.Fatalf("Failed unmarshalling response: %v", err)
+	}
+	if len(history) != 2 || history[1].From != car.StatusAvailable || history[1].To != car.StatusReserved {
+		t.Fatalf("Unexpected history: %v", history)
+	}
+}
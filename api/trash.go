@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/YoungOak/GoAPI/data"
+)
+
+func (h *Handler) DELETECar(w http.ResponseWriter, r *http.Request) {
+	id := carID(r)
+
+	err := h.cars.Delete(r.Context(), id)
+	if err != nil {
+		if _, notFound := err.(data.ErrorRecordNotFound); notFound {
+			h.logger.WarnContext(r.Context(), err.Error())
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(err.Error()))
+		} else {
+			h.unexpectedError(w, r, "error deleting car", err)
+		}
+		return
+	}
+
+	h.logger.Info(fmt.Sprintf("moved car with id: '%s' to the trash", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// trashedCar is a data.TrashedRecord with the car in the shape of the
+// version of the request.
+type trashedCar struct {
+	Car       carJSON   `json:"car"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func (h *Handler) GETTrash(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowedError(w, r)
+		return
+	}
+
+	trash, err := h.cars.Trash(r.Context())
+	if err != nil {
+		h.unexpectedError(w, r, "error listing trash", err)
+		return
+	}
+	h.logger.Info(fmt.Sprintf("listing %d trashed cars", len(trash)))
+	versioned := make([]trashedCar, 0, len(trash))
+	for _, trashed := range trash {
+		record, err := h.version.marshal(trashed.Record)
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), fmt.Sprintf("error marshalling record: %s", err.Error()))
+			internalServerError(w, r)
+			return
+		}
+		versioned = append(versioned, trashedCar{record, trashed.DeletedAt})
+	}
+	h.writeJSON(w, r, versioned)
+}
+
+func (h *Handler) POSTRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowedError(w, r)
+		return
+	}
+
+	id := carID(r)
+
+	record, err := h.cars.Restore(r.Context(), id)
+	if err != nil {
+		_, notTrashed := err.(data.ErrorNotInTrash)
+		_, vinExists := err.(data.ErrorVINAlreadyExists)
+		if notTrashed {
+			h.logger.WarnContext(r.Context(), err.Error())
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(err.Error()))
+		} else if vinExists {
+			h.logger.WarnContext(r.Context(), err.Error())
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(err.Error()))
+		} else {
+			h.unexpectedError(w, r, "error restoring car", err)
+		}
+		return
+	}
+
+	h.logger.Info(fmt.Sprintf("restored car with id: '%s'", id))
+	h.writeJSON(w, r, h.record(record))
+}
+
+// PurgeTrash permanently removes the cars trashed longer than retention,
+// checking every interval until ctx is done.
+func (h *Handler) PurgeTrash(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := h.cars.Purge(ctx, retention)
+			for _, id := range purged {
+				h.logger.Info(fmt.Sprintf("purged car with id: '%s' from the trash", id))
+			}
+			if err != nil {
+				h.logger.WarnContext(ctx, fmt.Sprintf("error purging trash: %s", err.Error()))
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+func TestDELETECarAndRestore(t *testing.T) {
+	ctx := context.Background()
+
+	cars := data.NewManager()
+	h := newTestHandler(cars)
+	_ = cars.Add(ctx, testRecord)
+
+	serve := func(handler http.HandlerFunc, method, target string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, target, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := serve(h.carHandler, http.MethodDelete, "/car?id=123"); rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected response code %v, got %v", http.StatusNoContent, rr.Code)
+	}
+	if rr := serve(h.carHandler, http.MethodDelete, "/car?id=123"); rr.Code != http.StatusNotFound {
+		t.Fatalf("Expected response code %v deleting twice, got %v", http.StatusNotFound, rr.Code)
+	}
+	if rr := serve(h.carHandler, http.MethodGet, "/car?id=123"); rr.Code != http.StatusNotFound {
+		t.Fatalf("Expected trashed car to be hidden, got %v", rr.Code)
+	}
+
+	rr := serve(h.GETTrash, http.MethodGet, "/cars/trash")
+	var trash []data.TrashedRecord
+	if err := json.Unmarshal(rr.Body.Bytes(), &trash); err != nil {
+		t.Fatalf("Failed unmarshalling response: %v", err)
+	}
+	if len(trash) != 1 || !reflect.DeepEqual(trash[0].Record, testRecord) {
+		t.Fatalf("Unexpected trash: %v", trash)
+	}
+
+	rr = serve(h.POSTRestore, http.MethodPost, "/car/restore?id=123")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected response code %v, got %v", http.StatusOK, rr.Code)
+	}
+	var restored car.Record
+	if err := json.Unmarshal(rr.Body.Bytes(), &restored); err != nil {
+		t.Fatalf("Failed unmarshalling response: %v", err)
+	}
+	if !reflect.DeepEqual(restored, testRecord) {
+		t.Fatalf("Unexpected record restored, wanted: %v, got: %v", testRecord, restored)
+	}
+
+	if rr := serve(h.POSTRestore, http.MethodPost, "/car/restore?id=123"); rr.Code != http.StatusNotFound {
+		t.Fatalf("Expected response code %v restoring twice, got %v", http.StatusNotFound, rr.Code)
+	}
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/YoungOak/GoAPI/internal/openapi"
+	"github.com/YoungOak/GoAPI/server"
+)
+
+const jsonMediaType = "application/json"
+
+/*
+validate wraps the handler of a path with the checks of its operations in
+document. Invalid requests are answered as bodyError does and never
+reach next.
+Methods the document does not list are passed on for next to refuse.
+*/
+func (h *Handler) validate(document *openapi.Document, item openapi.PathItem, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		operation, documented := item[strings.ToLower(r.Method)]
+		if !documented {
+			next(w, r)
+			return
+		}
+
+		if err := h.validateRequest(document, operation, w, r); err != nil {
+			h.bodyError(w, r, err)
+			return
+		}
+		if !h.validateResponses {
+			next(w, r)
+			return
+		}
+
+		recorder := &responseRecorder{header: make(http.Header)}
+		next(recorder, r)
+		if err := validateResponse(document, operation, recorder); err != nil {
+			h.logger.ErrorContext(r.Context(), fmt.Sprintf("invalid response to %s %s: %s", r.Method, r.URL.Path, err.Error()))
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(fmt.Sprintf("invalid response: %s", err.Error())))
+			return
+		}
+		recorder.writeTo(w)
+	}
+}
+
+// validateRequest checks the parameters and the JSON body of r. The body
+// is read and put back for the handler.
+func (h *Handler) validateRequest(document *openapi.Document, operation *openapi.Operation, w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	for _, p := range operation.Parameters {
+		var raw string
+		switch p.In {
+		case "header":
+			raw = r.Header.Get(p.Name)
+		case "path":
+			raw = server.PathValue(r, p.Name)
+		default:
+			raw = query.Get(p.Name)
+		}
+		if err := document.ValidateParameter(p, raw); err != nil {
+			// Reported as the handlers would, a missing one aside.
+			if raw == "" {
+				return err
+			}
+			return ErrorInvalidParameter{p.Name, raw}
+		}
+	}
+
+	if operation.RequestBody == nil {
+		return nil
+	}
+	media, isJSON := operation.RequestBody.Content[jsonMediaType]
+	if !isJSON {
+		return nil
+	}
+	body, err := h.readBody(w, r)
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return document.ValidateJSON(media.Schema, body, "body")
+}
+
+// validateResponse checks the status, content type and JSON body of a
+// response.
+func validateResponse(document *openapi.Document, operation *openapi.Operation, response *responseRecorder) error {
+	documented, ok := operation.Responses[strconv.Itoa(response.status)]
+	if !ok {
+		return openapi.ErrorInvalidValue{Where: "status", Reason: fmt.Sprintf("%d is not documented", response.status)}
+	}
+
+	body := response.body.Bytes()
+	if len(documented.Content) == 0 {
+		if len(body) > 0 {
+			return openapi.ErrorInvalidValue{Where: "body", Reason: "expected none"}
+		}
+		return nil
+	}
+
+	// As http.ResponseWriter does, sniff the type when the handler did
+	// not set one.
+	contentType := response.sent.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return openapi.ErrorInvalidValue{Where: "Content-Type", Reason: err.Error()}
+	}
+	// The document only lists JSON, which the version's type is too.
+	if versionOf(mediaType) != nil {
+		mediaType = jsonMediaType
+	}
+	media, ok := documented.Content[mediaType]
+	if !ok {
+		return openapi.ErrorInvalidValue{Where: "Content-Type", Reason: fmt.Sprintf("%s is not documented", mediaType)}
+	}
+	if mediaType == jsonMediaType {
+		return document.ValidateJSON(media.Schema, body, "body")
+	}
+	return nil
+}
+
+// responseRecorder buffers a response to check it before sending it.
+type responseRecorder struct {
+	header http.Header
+	// sent is the header as of WriteHeader, later changes are lost as
+	// they would be on the connection.
+	sent   http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.sent != nil {
+		return
+	}
+	r.sent = r.header.Clone()
+	r.status = status
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) writeTo(w http.ResponseWriter) {
+	r.WriteHeader(http.StatusOK)
+	for name, values := range r.sent {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(r.status)
+	w.Write(r.body.Bytes())
+}
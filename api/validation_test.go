@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/servertest"
+)
+
+func TestRequestValidation(t *testing.T) {
+	router := servertest.NewRouter()
+	newTestHandler(data.NewManager(), WithRequestValidation()).Register(router)
+
+	valid := `{"id": "123", "make": "Toyota", "model": "Camry", "category": "Sedan", "package": "Standard",
+		"color": "Blue", "year": 2020, "mileage": 1000, "price": 20000}`
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{"valid car", http.MethodPost, "/car", valid, http.StatusCreated, ""},
+		{"string year", http.MethodPost, "/car", strings.Replace(valid, "2020", `"2020"`, 1), http.StatusBadRequest, "body.year: expected integer, got string"},
+		{"missing make", http.MethodPost, "/car", strings.Replace(valid, `"make": "Toyota",`, "", 1), http.StatusBadRequest, "body.make: missing"},
+		{"unknown status", http.MethodPut, "/car", strings.Replace(valid, "{", `{"status": "lost",`, 1), http.StatusBadRequest, "body.status: lost is not one of"},
+		{"invalid price", http.MethodPost, "/car", strings.Replace(valid, "20000", `"20000"`, 1), http.StatusBadRequest, "body.price: expected number, got string, or expected object, got string"},
+		{"invalid JSON", http.MethodPost, "/car", "{", http.StatusBadRequest, "body: invalid JSON"},
+		{"negative limit", http.MethodGet, "/cars?limit=-1", "", http.StatusBadRequest, "query parameter 'limit' invalid value: '-1'"},
+		{"unknown unit", http.MethodGet, "/cars?units=furlongs", "", http.StatusBadRequest, "query parameter 'units' invalid value: 'furlongs'"},
+		{"missing id", http.MethodDelete, "/car", "", http.StatusBadRequest, "query parameter 'id': missing"},
+		{"undocumented method", http.MethodPatch, "/car", "", http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if rr.Code != tt.wantCode || !strings.HasPrefix(rr.Body.String(), tt.wantBody) {
+				t.Fatalf("Expected %v %q, got %v %q", tt.wantCode, tt.wantBody, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestResponseValidation(t *testing.T) {
+	h := newTestHandler(data.NewManager(), WithResponseValidation())
+	document := h.OpenAPI()
+
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "documented",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`[]`))
+			},
+			wantCode: http.StatusOK,
+			wantBody: "[]",
+		},
+		{
+			name: "undocumented status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: "invalid response: status: 418 is not documented",
+		},
+		{
+			name: "header set too late",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`[]`))
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: "invalid response: Content-Type: text/plain is not documented",
+		},
+		{
+			name: "invalid body",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`[{"id": 123}]`))
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: "invalid response: body[0].make: missing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			h.validate(&document, document.Paths["/cars"], tt.handler)(rr, httptest.NewRequest(http.MethodGet, "/cars", nil))
+			if rr.Code != tt.wantCode || rr.Body.String() != tt.wantBody {
+				t.Fatalf("Expected %v %q, got %v %q", tt.wantCode, tt.wantBody, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+/*
+apiVersion is a version of the API, served under its name as a prefix,
+such as /v2/cars, or on the unversioned routes when asked for with its
+media type. Versions differ in the shape of the cars in their bodies,
+mapped to and from car.Record so the model can change without breaking
+the clients of a version.
+*/
+type apiVersion struct {
+	name string
+	// release is the version of its OpenAPI document.
+	release string
+	// record is a value of the type of the cars of the version.
+	record      any
+	toResponse  func(car.Record) any
+	fromRequest func(body []byte) (car.Record, error)
+}
+
+var (
+	v1 = &apiVersion{
+		name:        "v1",
+		release:     "1.0.0",
+		record:      recordV1{},
+		toResponse:  recordToV1,
+		fromRequest: recordFromV1,
+	}
+	v2 = &apiVersion{
+		name:    "v2",
+		release: "2.0.0",
+		record:  car.Record{},
+		toResponse: func(record car.Record) any {
+			return record
+		},
+		fromRequest: func(body []byte) (car.Record, error) {
+			var record car.Record
+			err := decodeStrict(body, &record)
+			return record, err
+		},
+	}
+
+	// versions lists the versions of the API, the first being served by
+	// default on the unversioned routes.
+	versions = []*apiVersion{v1, v2}
+)
+
+const mediaTypePrefix = "application/vnd.cars."
+
+// mediaType is the JSON media type of the version, such as
+// application/vnd.cars.v2+json.
+func (v *apiVersion) mediaType() string {
+	return mediaTypePrefix + v.name + "+json"
+}
+
+// marshal returns the JSON of record in the shape of the version.
+func (v *apiVersion) marshal(record car.Record) (carJSON, error) {
+	return json.Marshal(v.toResponse(record))
+}
+
+/*
+recordV1 is a car as version 1 of the API has it: car.Record as it was
+when versions were introduced, but for its price, a number of whole units
+as it was before prices had a currency, with the currency beside it.
+Clients reading the price as an integer keep working for every price
+without cents.
+*/
+type recordV1 struct {
+	ID          string           `json:"id,omitempty" doc:"Unique ID of the car, generated when missing on creation"`
+	Make        string           `json:"make"`
+	Model       string           `json:"model"`
+	Category    string           `json:"category"`
+	Package     string           `json:"package"`
+	Color       string           `json:"color"`
+	Year        int              `json:"year"`
+	Mileage     int              `json:"mileage"`
+	MileageUnit car.DistanceUnit `json:"mileage_unit,omitempty" doc:"Unit of the mileage, miles when missing"`
+	Price       priceV1          `json:"price"`
+	Currency    string           `json:"currency,omitempty" doc:"ISO 4217 currency code of the price, USD when missing"`
+	VIN         string           `json:"vin,omitempty" doc:"ISO 3779 vehicle identification number, unique when present"`
+	Status      car.Status       `json:"status,omitempty" doc:"Sale status of the car, available when missing on creation"`
+}
+
+/*
+priceV1 is the price of a car in v1 as JSON: a number of whole units of
+the currency, such as 199.99. It is read as a car.Money object too, as
+v1 first wrote prices that way.
+*/
+type priceV1 json.RawMessage
+
+func (p priceV1) MarshalJSON() ([]byte, error) {
+	return json.RawMessage(p).MarshalJSON()
+}
+
+func (p *priceV1) UnmarshalJSON(data []byte) error {
+	*p = append((*p)[:0], data...)
+	return nil
+}
+
+// money returns the price in currency, USD when empty.
+func (p priceV1) money(currency string) (car.Money, error) {
+	currency = strings.ToUpper(currency)
+	if len(p) == 0 {
+		return car.Money{}, nil
+	}
+	if p[0] == '{' {
+		var money car.Money
+		if err := decodeStrict(p, &money); err != nil {
+			return car.Money{}, err
+		}
+		if currency != "" && currency != money.Currency {
+			return car.Money{}, ErrorInvalidBody{fmt.Sprintf("currency '%s' differs from the one of the price, '%s'", currency, money.Currency)}
+		}
+		return money, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return car.Money{}, ErrorInvalidBody{err.Error()}
+	}
+	units, ok := value.(json.Number)
+	if !ok {
+		return car.Money{}, ErrorInvalidBody{"price is neither a number nor an object"}
+	}
+	if currency == "" {
+		currency = car.DefaultCurrency
+	}
+	money, err := car.ParseUnits(string(units), currency)
+	if err != nil {
+		return car.Money{}, ErrorInvalidBody{err.Error()}
+	}
+	return money, nil
+}
+
+func recordToV1(record car.Record) any {
+	return recordV1{
+		ID:          record.ID,
+		Make:        record.Make,
+		Model:       record.Model,
+		Category:    record.Category,
+		Package:     record.Package,
+		Color:       record.Color,
+		Year:        record.Year,
+		Mileage:     record.Mileage,
+		MileageUnit: record.MileageUnit,
+		Price:       priceV1(record.Price.Units()),
+		Currency:    record.Price.Currency,
+		VIN:         record.VIN,
+		Status:      record.Status,
+	}
+}
+
+func recordFromV1(body []byte) (car.Record, error) {
+	var record recordV1
+	if err := decodeStrict(body, &record); err != nil {
+		return car.Record{}, err
+	}
+	price, err := record.Price.money(record.Currency)
+	if err != nil {
+		return car.Record{}, err
+	}
+	return car.Record{
+		ID:          record.ID,
+		Make:        record.Make,
+		Model:       record.Model,
+		Category:    record.Category,
+		Package:     record.Package,
+		Color:       record.Color,
+		Year:        record.Year,
+		Mileage:     record.Mileage,
+		MileageUnit: record.MileageUnit,
+		Price:       price,
+		VIN:         record.VIN,
+		Status:      record.Status,
+	}, nil
+}
+
+/*
+carJSON is a car nested in a body, as JSON in the shape of the version of
+the request. It is written and read with the mappers of the version, and
+the OpenAPI document of a version shows the schema of its cars in its
+place.
+*/
+type carJSON json.RawMessage
+
+func (c carJSON) MarshalJSON() ([]byte, error) {
+	return json.RawMessage(c).MarshalJSON()
+}
+
+func (c *carJSON) UnmarshalJSON(data []byte) error {
+	*c = append((*c)[:0], data...)
+	return nil
+}
+
+type ErrorNotAcceptable struct {
+	Accept string
+}
+
+func (e ErrorNotAcceptable) Error() string {
+	return fmt.Sprintf("no version of the API serves '%s'", e.Accept)
+}
+
+/*
+negotiate picks the version serving a request to an unversioned route:
+the one whose media type the Accept header lists, else the one of the
+Content-Type of the body, else the first. Accepting only media types of
+unknown versions is an error.
+*/
+func negotiate(r *http.Request) (*apiVersion, error) {
+	accept := r.Header.Get("Accept")
+	acceptsAny := accept == ""
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		if v := versionOf(mediaType); v != nil {
+			return v, nil
+		}
+		if !strings.HasPrefix(mediaType, mediaTypePrefix) {
+			acceptsAny = true
+		}
+	}
+	if !acceptsAny {
+		return nil, ErrorNotAcceptable{accept}
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil {
+		if v := versionOf(mediaType); v != nil {
+			return v, nil
+		}
+	}
+	return versions[0], nil
+}
+
+// versionOf returns the version of a media type, nil for other types.
+func versionOf(mediaType string) *apiVersion {
+	for _, v := range versions {
+		if mediaType == v.mediaType() {
+			return v
+		}
+	}
+	return nil
+}
+
+// negotiated serves a route with the handler of the version negotiate
+// picks among byVersion.
+func (h *Handler) negotiated(byVersion map[*apiVersion]http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		v, err := negotiate(r)
+		if err != nil {
+			h.logger.WarnContext(r.Context(), err.Error())
+			w.WriteHeader(http.StatusNotAcceptable)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		handler, ok := byVersion[v]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// withVersion returns a copy of the Handler serving version v.
+func (h *Handler) withVersion(v *apiVersion) *Handler {
+	versioned := *h
+	versioned.version = v
+	return &versioned
+}
+
+// contentType is the type of the JSON responses to r, the media type of
+// the version when the request accepts it.
+func (h *Handler) contentType(r *http.Request) string {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(part); err == nil && mediaType == h.version.mediaType() {
+			return mediaType
+		}
+	}
+	return jsonMediaType
+}
+
+// record returns record in the shape of the version of the Handler.
+func (h *Handler) record(record car.Record) any {
+	return h.version.toResponse(record)
+}
+
+// decodeRecord reads the car in the body of r, in the shape of the
+// version of the Handler. The request is answered when it cannot be, and
+// false returned.
+func (h *Handler) decodeRecord(w http.ResponseWriter, r *http.Request) (car.Record, bool) {
+	body, err := h.readBody(w, r)
+	if err != nil {
+		h.bodyError(w, r, err)
+		return car.Record{}, false
+	}
+	record, err := h.version.fromRequest(body)
+	if err != nil {
+		h.bodyError(w, r, err)
+		return car.Record{}, false
+	}
+	return record, true
+}
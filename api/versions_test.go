@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/servertest"
+)
+
+// TestRecordV1 pins the JSON of a car in v1, which must not change.
+func TestRecordV1(t *testing.T) {
+	record := testRecord
+	record.Year = 2020
+	body, err := json.Marshal(v1.toResponse(record))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"id":"123","make":"Toyota","model":"Camry","category":"Sedan","package":"Standard","color":"Blue",` +
+		`"year":2020,"mileage":1000,"price":10000,"currency":"USD","status":"available"}`
+	if string(body) != want {
+		t.Fatalf("unexpected v1 JSON, wanted: %s, got: %s", want, body)
+	}
+
+	decoded, err := v1.fromRequest(body)
+	if err != nil || decoded != record {
+		t.Fatalf("expected the record back, got: %+v, error: %v", decoded, err)
+	}
+}
+
+func TestPriceV1(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    string
+		currency string
+		want     car.Money
+		wantErr  bool
+	}{
+		{"whole units", `20000`, "", car.NewMoney(20000, "USD"), false},
+		{"cents", `199.99`, "", car.Money{Amount: 19999, Currency: "USD"}, false},
+		{"currency beside", `1500`, "jpy", car.NewMoney(1500, "JPY"), false},
+		{"object", `{"amount": 1999, "currency": "EUR"}`, "", car.Money{Amount: 1999, Currency: "EUR"}, false},
+		{"object and same currency", `{"amount": 1999, "currency": "EUR"}`, "EUR", car.Money{Amount: 1999, Currency: "EUR"}, false},
+		{"object and other currency", `{"amount": 1999, "currency": "EUR"}`, "USD", car.Money{}, true},
+		{"finer than the currency", `19.5`, "JPY", car.Money{}, true},
+		{"string", `"20000"`, "", car.Money{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := priceV1(tt.price).money(tt.currency)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Fatalf("unexpected price, wanted: %v, error %v, got: %v, error: %v", tt.want, tt.wantErr, got, err)
+			}
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		want        *apiVersion
+		wantErr     bool
+	}{
+		{"no headers", "", "", v1, false},
+		{"json", "application/json", "", v1, false},
+		{"v2", "application/vnd.cars.v2+json", "", v2, false},
+		{"v1 over v2", "application/vnd.cars.v1+json, application/vnd.cars.v2+json;q=0.5", "", v1, false},
+		{"unknown version only", "application/vnd.cars.v9+json", "", nil, true},
+		{"unknown version or json", "application/vnd.cars.v9+json, application/json", "", v1, false},
+		{"body in v2", "", "application/vnd.cars.v2+json", v2, false},
+		{"accept over body", "application/vnd.cars.v1+json", "application/vnd.cars.v2+json", v1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/cars", nil)
+			r.Header.Set("Accept", tt.accept)
+			r.Header.Set("Content-Type", tt.contentType)
+			got, err := negotiate(r)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Fatalf("expected %v, error %v, got %v, %v", tt.want, tt.wantErr, got, err)
+			}
+		})
+	}
+}
+
+func TestVersionedRoutes(t *testing.T) {
+	router := servertest.NewRouter()
+	newTestHandler(data.NewManager(), WithResponseValidation()).Register(router)
+
+	body, _ := json.Marshal(testRecord)
+	tests := []struct {
+		name            string
+		method          string
+		target          string
+		accept          string
+		body            string
+		wantCode        int
+		wantContentType string
+	}{
+		{"add in v2", http.MethodPost, "/v2/cars", "", string(body), http.StatusCreated, "application/json"},
+		{"get in v1", http.MethodGet, "/v1/cars/123", "", "", http.StatusOK, "application/json"},
+		{"get in v2", http.MethodGet, "/v2/cars/123", "", "", http.StatusOK, "application/json"},
+		{"negotiated v1", http.MethodGet, "/cars/123", "", "", http.StatusOK, "application/json"},
+		{"negotiated v2", http.MethodGet, "/cars/123", "application/vnd.cars.v2+json", "", http.StatusOK, "application/vnd.cars.v2+json"},
+		{"v2 asked for by type", http.MethodGet, "/v2/cars", "application/vnd.cars.v2+json", "", http.StatusOK, "application/vnd.cars.v2+json"},
+		{"unknown version", http.MethodGet, "/cars/123", "application/vnd.cars.v9+json", "", http.StatusNotAcceptable, ""},
+		{"deprecated in v1", http.MethodGet, "/v1/car?id=123", "", "", http.StatusOK, "application/json"},
+		{"no deprecated routes in v2", http.MethodGet, "/v2/car?id=123", "", "", http.StatusNotFound, ""},
+		{"negotiated v2 without deprecated routes", http.MethodGet, "/car?id=123", "application/vnd.cars.v2+json", "", http.StatusNotFound, ""},
+		{"unknown version prefix", http.MethodGet, "/v9/cars", "", "", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			r.Header.Set("Accept", tt.accept)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, r)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("Expected response code %v, got %v: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+			if tt.wantContentType != "" && rr.Header().Get("Content-Type") != tt.wantContentType {
+				t.Fatalf("Expected Content-Type %s, got %s", tt.wantContentType, rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
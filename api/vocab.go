@@ -0,0 +1,121 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/YoungOak/GoAPI/vocab"
+)
+
+type termRequest struct {
+	Name string `json:"name"`
+}
+
+func (h *Handler) makesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.writeJSON(w, r, h.vocabulary.Makes())
+	case http.MethodPost:
+		h.withTerm(w, r, http.StatusCreated, func(name string) (string, error) {
+			return h.vocabulary.AddMake(name)
+		})
+	case http.MethodPut:
+		h.withTerm(w, r, http.StatusAccepted, func(name string) (string, error) {
+			return h.vocabulary.RenameMake(r.URL.Query().Get("name"), name)
+		})
+	case http.MethodDelete:
+		h.deleteTerm(w, r, h.vocabulary.DeleteMake(r.URL.Query().Get("name")))
+	default:
+		methodNotAllowedError(w, r)
+	}
+}
+
+func (h *Handler) modelsHandler(w http.ResponseWriter, r *http.Request) {
+	makeName := r.URL.Query().Get("make")
+
+	switch r.Method {
+	case http.MethodGet:
+		models, err := h.vocabulary.Models(makeName)
+		if err != nil {
+			h.vocabError(w, r, err)
+			return
+		}
+		h.writeJSON(w, r, models)
+	case http.MethodPost:
+		h.withTerm(w, r, http.StatusCreated, func(name string) (string, error) {
+			return h.vocabulary.AddModel(makeName, name)
+		})
+	case http.MethodPut:
+		h.withTerm(w, r, http.StatusAccepted, func(name string) (string, error) {
+			return h.vocabulary.RenameModel(makeName, r.URL.Query().Get("name"), name)
+		})
+	case http.MethodDelete:
+		h.deleteTerm(w, r, h.vocabulary.DeleteModel(makeName, r.URL.Query().Get("name")))
+	default:
+		methodNotAllowedError(w, r)
+	}
+}
+
+func (h *Handler) categoriesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.writeJSON(w, r, h.vocabulary.Categories())
+	case http.MethodPost:
+		h.withTerm(w, r, http.StatusCreated, func(name string) (string, error) {
+			return h.vocabulary.AddCategory(name)
+		})
+	case http.MethodPut:
+		h.withTerm(w, r, http.StatusAccepted, func(name string) (string, error) {
+			return h.vocabulary.RenameCategory(r.URL.Query().Get("name"), name)
+		})
+	case http.MethodDelete:
+		h.deleteTerm(w, r, h.vocabulary.DeleteCategory(r.URL.Query().Get("name")))
+	default:
+		methodNotAllowedError(w, r)
+	}
+}
+
+// withTerm decodes the term of the request body and saves it with save,
+// answering status on success.
+func (h *Handler) withTerm(w http.ResponseWriter, r *http.Request, status int, save func(name string) (string, error)) {
+	var term termRequest
+	if !h.decodeBody(w, r, &term) {
+		return
+	}
+
+	name, err := save(term.Name)
+	if err != nil {
+		h.vocabError(w, r, err)
+		return
+	}
+
+	h.logger.Info(fmt.Sprintf("saved vocabulary term: '%s'", name))
+	w.WriteHeader(status)
+	w.Write([]byte(fmt.Sprintf("saved '%s'", name)))
+}
+
+func (h *Handler) deleteTerm(w http.ResponseWriter, r *http.Request, err error) {
+	if err != nil {
+		h.vocabError(w, r, err)
+		return
+	}
+
+	h.logger.Info(fmt.Sprintf("deleted vocabulary term: '%s'", r.URL.Query().Get("name")))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) vocabError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err.(type) {
+	case vocab.ErrorTermEmpty, vocab.ErrorTermExists:
+		h.logger.WarnContext(r.Context(), err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+	case vocab.ErrorTermNotFound:
+		h.logger.WarnContext(r.Context(), err.Error())
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(err.Error()))
+	default:
+		h.logger.ErrorContext(r.Context(), fmt.Sprintf("error saving vocabulary: %s", err.Error()))
+		internalServerError(w, r)
+	}
+}
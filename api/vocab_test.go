@@ -0,0 +1,80 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/vocab"
+)
+
+func TestVocabularyHandlers(t *testing.T) {
+	registry := vocab.NewRegistry()
+	cars := data.NewManager(data.WithVocabulary(registry))
+	h := newTestHandler(cars, WithVocabulary(registry))
+
+	serve := func(handler http.HandlerFunc, method, target string, body any) *httptest.ResponseRecorder {
+		var reqBody bytes.Buffer
+		if body != nil {
+			_ = json.NewEncoder(&reqBody).Encode(body)
+		}
+		req, err := http.NewRequest(method, target, &reqBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := serve(h.makesHandler, http.MethodPost, "/makes", termRequest{" toyota "}); rr.Code != http.StatusCreated {
+		t.Fatalf("Expected response code %v, got %v", http.StatusCreated, rr.Code)
+	}
+	if rr := serve(h.makesHandler, http.MethodPost, "/makes", termRequest{"TOYOTA"}); rr.Code != http.StatusBadRequest {
+		t.Fatalf("Expected response code %v for duplicate make, got %v", http.StatusBadRequest, rr.Code)
+	}
+	if rr := serve(h.makesHandler, http.MethodPut, "/makes?name=TOYOTA", termRequest{"Toyota"}); rr.Code != http.StatusAccepted {
+		t.Fatalf("Expected response code %v, got %v", http.StatusAccepted, rr.Code)
+	}
+	if rr := serve(h.modelsHandler, http.MethodPost, "/models?make=toyota", termRequest{"Camry"}); rr.Code != http.StatusCreated {
+		t.Fatalf("Expected response code %v, got %v", http.StatusCreated, rr.Code)
+	}
+	if rr := serve(h.modelsHandler, http.MethodGet, "/models?make=Mazda", nil); rr.Code != http.StatusNotFound {
+		t.Fatalf("Expected response code %v for unknown make, got %v", http.StatusNotFound, rr.Code)
+	}
+
+	rr := serve(h.makesHandler, http.MethodGet, "/makes", nil)
+	var makes []string
+	_ = json.Unmarshal(rr.Body.Bytes(), &makes)
+	if !reflect.DeepEqual(makes, []string{"Toyota"}) {
+		t.Fatalf("Unexpected makes, got: %v", makes)
+	}
+
+	record := testRecord
+	record.Make = "TOYOTA"
+	record.Model = "camry "
+	rr = serve(h.POSTCar, http.MethodPost, "/car", record)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected response code %v, got %v: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	var gotRecord car.Record
+	_ = json.Unmarshal(rr.Body.Bytes(), &gotRecord)
+	if gotRecord.Make != "Toyota" || gotRecord.Model != "Camry" {
+		t.Fatalf("Expected make and model to be normalized, got: %v %v", gotRecord.Make, gotRecord.Model)
+	}
+
+	record.ID = "456"
+	record.Model = "Corolla"
+	if rr := serve(h.POSTCar, http.MethodPost, "/car", record); rr.Code != http.StatusBadRequest {
+		t.Fatalf("Expected response code %v for unknown model, got %v", http.StatusBadRequest, rr.Code)
+	}
+
+	if rr := serve(h.makesHandler, http.MethodDelete, "/makes?name=toyota", nil); rr.Code != http.StatusNoContent {
+		t.Fatalf("Expected response code %v, got %v", http.StatusNoContent, rr.Code)
+	}
+}
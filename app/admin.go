@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/YoungOak/GoAPI/api"
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/carfile"
+	"github.com/YoungOak/GoAPI/internal/config"
+	"github.com/YoungOak/GoAPI/snapshot"
+)
+
+const usage = `Usage: app [command] [arguments]
+
+Commands:
+  serve [-restore name]  serve the API, the default command
+  migrate                prepare the snapshot directory for this version
+  snapshot [-list]       save a snapshot of the storage, or list them
+  restore <name>         make a snapshot the current storage
+  import <file>          add every car of a .csv or .json file
+  export <file>          write every car to a .csv or .json file
+  validate <file>        check the cars of a file could be imported
+
+Every command but serve and validate needs CARS_SNAPSHOT_DIR, and should
+only run while the API is stopped.
+`
+
+// latestSnapshot names the most recent snapshot on the command line.
+const latestSnapshot = "latest"
+
+// errUsage is returned for invalid command lines, after which usage is
+// printed.
+var errUsage = errors.New("invalid usage")
+
+// ErrorNoStorage is returned by the commands working on the snapshot
+// directory when none is configured.
+type ErrorNoStorage struct{}
+
+func (e ErrorNoStorage) Error() string {
+	return "CARS_SNAPSHOT_DIR is not set, the store is only kept in memory"
+}
+
+/*
+admin runs one of the commands maintaining the storage without the API.
+The storage of the API is its latest snapshot: commands load it, and the
+ones changing it save a new snapshot, so the previous ones can still be
+restored. The API starts from the latest snapshot, so it serves the
+changes once started again.
+*/
+func admin(ctx context.Context, cfg config.Config, command string, args []string, stdout io.Writer) error {
+	switch command {
+	case "migrate":
+		return migrate(ctx, cfg, args, stdout)
+	case "snapshot":
+		return saveSnapshot(ctx, cfg, args, stdout)
+	case "restore":
+		return restore(ctx, cfg, args, stdout)
+	case "import":
+		return importFile(ctx, cfg, args, stdout)
+	case "export":
+		return exportFile(ctx, cfg, args, stdout)
+	case "validate":
+		return validate(ctx, cfg, args, stdout)
+	}
+	return errUsage
+}
+
+// storage is the store of the API as kept in the snapshot directory.
+type storage struct {
+	cars      data.Manager
+	snapshots *snapshot.Store
+	// latest is the snapshot the cars were loaded from, empty when the
+	// directory has none.
+	latest string
+}
+
+// openStorage loads the latest snapshot of the configured directory.
+func openStorage(ctx context.Context, cfg config.Config) (*storage, error) {
+	if cfg.SnapshotDir == "" {
+		return nil, ErrorNoStorage{}
+	}
+	cars, _, err := newManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := snapshot.NewStore(cfg.SnapshotDir, clock.System, snapshot.WithRetention(cfg.SnapshotKeep))
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := latest(snapshots)
+	if err != nil || name == "" {
+		return &storage{cars: cars, snapshots: snapshots}, err
+	}
+	state, err := snapshots.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := cars.Load(ctx, state); err != nil {
+		return nil, err
+	}
+	return &storage{cars: cars, snapshots: snapshots, latest: name}, nil
+}
+
+// save writes the cars to a new snapshot, which becomes the latest.
+func (s *storage) save(ctx context.Context) (snapshot.Info, error) {
+	state, err := s.cars.Dump(ctx)
+	if err != nil {
+		return snapshot.Info{}, err
+	}
+	info, err := s.snapshots.Save(state)
+	if err != nil {
+		return snapshot.Info{}, err
+	}
+	s.latest = info.Name
+	return info, nil
+}
+
+// latest returns the name of the most recent snapshot, empty when there
+// is none.
+func latest(snapshots *snapshot.Store) (string, error) {
+	list, err := snapshots.List()
+	if err != nil || len(list) == 0 {
+		return "", err
+	}
+	return list[0].Name, nil
+}
+
+/*
+migrate prepares the snapshot directory for this version of the API. It
+is created with an empty snapshot when it has none, and the latest
+snapshot must be of a version this one can load.
+*/
+func migrate(ctx context.Context, cfg config.Config, args []string, stdout io.Writer) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	s, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	if s.latest != "" {
+		fmt.Fprintf(stdout, "snapshot %s is at version %d, nothing to migrate\n", s.latest, snapshot.Version)
+		return nil
+	}
+	info, err := s.save(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "created empty snapshot %s\n", info.Name)
+	return nil
+}
+
+// saveSnapshot saves a copy of the storage to restore later, or lists the
+// snapshots with -list.
+func saveSnapshot(ctx context.Context, cfg config.Config, args []string, stdout io.Writer) error {
+	if len(args) > 1 || len(args) == 1 && args[0] != "-list" {
+		return errUsage
+	}
+	s, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		list, err := s.snapshots.List()
+		if err != nil {
+			return err
+		}
+		for _, info := range list {
+			fmt.Fprintf(stdout, "%s\t%d bytes\n", info.Name, info.Size)
+		}
+		return nil
+	}
+	info, err := s.save(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "saved snapshot %s\n", info.Name)
+	return nil
+}
+
+// restore saves the named snapshot again as the latest one.
+func restore(ctx context.Context, cfg config.Config, args []string, stdout io.Writer) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	s, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	state, err := s.snapshots.Load(args[0])
+	if err != nil {
+		return err
+	}
+	if err := s.cars.Load(ctx, state); err != nil {
+		return err
+	}
+	info, err := s.save(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "restored snapshot %s with %d cars as %s\n", args[0], len(state.Records), info.Name)
+	return nil
+}
+
+// importFile adds every car of a file to the storage. Nothing is saved
+// unless all of them are added.
+func importFile(ctx context.Context, cfg config.Config, args []string, stdout io.Writer) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	s, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	records, err := addAll(ctx, s.cars, args[0], stdout)
+	if err != nil {
+		return fmt.Errorf("%w, nothing imported", err)
+	}
+	info, err := s.save(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "imported %d cars into snapshot %s\n", records, info.Name)
+	return nil
+}
+
+func exportFile(ctx context.Context, cfg config.Config, args []string, stdout io.Writer) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	s, err := openStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	records, err := s.cars.List(ctx)
+	if err != nil {
+		return err
+	}
+	if err := carfile.Write(args[0], records); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "exported %d cars to %s\n", len(records), args[0])
+	return nil
+}
+
+/*
+validate checks the cars of a file against the configured rules and
+vocabulary, and against the storage when there is one, without saving
+anything.
+*/
+func validate(ctx context.Context, cfg config.Config, args []string, stdout io.Writer) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	var cars data.Manager
+	if cfg.SnapshotDir == "" {
+		var err error
+		if cars, _, err = newManager(cfg); err != nil {
+			return err
+		}
+	} else {
+		s, err := openStorage(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		cars = s.cars
+	}
+
+	records, err := addAll(ctx, cars, args[0], stdout)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%d cars valid\n", records)
+	return nil
+}
+
+/*
+addAll adds the cars of a file to cars, reporting each one rejected, and
+returns how many there were. They are added in a single transaction, so
+none is kept unless all of them are valid.
+*/
+func addAll(ctx context.Context, cars data.Manager, path string, stdout io.Writer) (int, error) {
+	records, err := carfile.Read(path)
+	if err != nil {
+		return 0, err
+	}
+
+	err = cars.Tx(ctx, func(tx data.Tx) error {
+		failed := 0
+		for i, record := range records {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			err := api.CheckID(record.ID)
+			if err == nil {
+				err = tx.Add(record)
+			}
+			if err != nil {
+				fmt.Fprintf(stdout, "car %d '%s': %v\n", i+1, record.ID, err)
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d cars invalid", failed, len(records))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
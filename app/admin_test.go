@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/internal/carfile"
+	"github.com/YoungOak/GoAPI/internal/config"
+)
+
+var testRecords = []car.Record{
+	{
+		ID: "1", Make: "Toyota", Model: "Camry", Category: "Sedan", Package: "Standard", Color: "Blue",
+		Year: 2020, Mileage: 1000, Price: car.NewMoney(10000, "USD"), Status: car.StatusAvailable,
+	},
+	{
+		ID: "2", Make: "Honda", Model: "Civic", Category: "Hatchback", Package: "Sport", Color: "Red",
+		Year: 2021, Mileage: 500, Price: car.NewMoney(30000, "EUR"), Status: car.StatusAvailable,
+	},
+}
+
+// runAdmin runs an admin command line and returns its output.
+func runAdmin(t *testing.T, cfg config.Config, args ...string) (string, error) {
+	t.Helper()
+	var stdout bytes.Buffer
+	err := admin(context.Background(), cfg, args[0], args[1:], &stdout)
+	return stdout.String(), err
+}
+
+func TestAdmin(t *testing.T) {
+	cfg := config.Config{SnapshotDir: filepath.Join(t.TempDir(), "snapshots")}
+	files := t.TempDir()
+	first, second := filepath.Join(files, "first.csv"), filepath.Join(files, "second.json")
+	_ = carfile.Write(first, testRecords[:1])
+	_ = carfile.Write(second, testRecords[1:])
+
+	if out, err := runAdmin(t, cfg, "migrate"); err != nil || !strings.HasPrefix(out, "created empty snapshot") {
+		t.Fatalf("unexpected migrate output: %q, %v", out, err)
+	}
+	if out, err := runAdmin(t, cfg, "migrate"); err != nil || !strings.HasSuffix(out, "nothing to migrate\n") {
+		t.Fatalf("unexpected second migrate output: %q, %v", out, err)
+	}
+
+	if _, err := runAdmin(t, cfg, "import", first); err != nil {
+		t.Fatalf("unexpected error importing: %v", err)
+	}
+	s, _ := openStorage(context.Background(), cfg)
+	afterFirst := s.latest
+
+	if out, err := runAdmin(t, cfg, "validate", second); err != nil || out != "1 cars valid\n" {
+		t.Fatalf("unexpected validate output: %q, %v", out, err)
+	}
+	if out, err := runAdmin(t, cfg, "validate", first); err == nil || !strings.Contains(out, "car 1 '1'") {
+		t.Fatalf("expected validating a duplicate to fail, got: %q, %v", out, err)
+	}
+	if _, err := runAdmin(t, cfg, "import", second); err != nil {
+		t.Fatalf("unexpected error importing: %v", err)
+	}
+
+	exported := filepath.Join(files, "exported.json")
+	if _, err := runAdmin(t, cfg, "export", exported); err != nil {
+		t.Fatalf("unexpected error exporting: %v", err)
+	}
+	if got, _ := carfile.Read(exported); !reflect.DeepEqual(got, testRecords) {
+		t.Fatalf("unexpected exported cars, wanted: %v, got: %v", testRecords, got)
+	}
+
+	if _, err := runAdmin(t, cfg, "restore", afterFirst); err != nil {
+		t.Fatalf("unexpected error restoring: %v", err)
+	}
+	_, _ = runAdmin(t, cfg, "export", exported)
+	if got, _ := carfile.Read(exported); !reflect.DeepEqual(got, testRecords[:1]) {
+		t.Fatalf("unexpected cars after restoring, wanted: %v, got: %v", testRecords[:1], got)
+	}
+
+	out, err := runAdmin(t, cfg, "snapshot", "-list")
+	if err != nil || strings.Count(out, "\n") != 4 || !strings.Contains(out, afterFirst) {
+		t.Fatalf("unexpected snapshot list: %q, %v", out, err)
+	}
+}
+
+func TestAdminImportAllOrNothing(t *testing.T) {
+	cfg := config.Config{SnapshotDir: t.TempDir()}
+	path := filepath.Join(t.TempDir(), "cars.json")
+	invalid := testRecords[1]
+	invalid.Make = ""
+	_ = carfile.Write(path, []car.Record{testRecords[0], invalid})
+
+	out, err := runAdmin(t, cfg, "import", path)
+	if err == nil || !strings.Contains(out, "car 2 '2'") {
+		t.Fatalf("expected import to fail, got: %q, %v", out, err)
+	}
+	if s, _ := openStorage(context.Background(), cfg); s.latest != "" {
+		t.Fatalf("expected nothing saved, got snapshot %s", s.latest)
+	}
+}
+
+func TestAdminUsage(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		args    []string
+		wantErr error
+	}{
+		{"unknown command", config.Config{SnapshotDir: t.TempDir()}, []string{"frobnicate"}, errUsage},
+		{"missing file", config.Config{SnapshotDir: t.TempDir()}, []string{"import"}, errUsage},
+		{"no snapshot directory", config.Config{}, []string{"export", "cars.json"}, ErrorNoStorage{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := runAdmin(t, tt.cfg, tt.args...); err != tt.wantErr {
+				t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
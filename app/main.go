@@ -1,19 +1,34 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
-	"github.com/YoungOak/GoAPI/internal/data"
-	"github.com/YoungOak/GoAPI/internal/server"
+	"github.com/YoungOak/GoAPI/api"
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/exchange"
+	"github.com/YoungOak/GoAPI/internal/config"
+	"github.com/YoungOak/GoAPI/server"
+	"github.com/YoungOak/GoAPI/snapshot"
+	"github.com/YoungOak/GoAPI/vocab"
 )
 
 var (
-	CarManager data.Manager
-	Router     server.Router
-
-	addr string = ":8080"
+	addr                     string        = ":8080"
+	idempotencyTTL           time.Duration = 24 * time.Hour
+	reservationSweepInterval time.Duration = time.Minute
+	trashPurgeInterval       time.Duration = time.Hour
 )
 
 /*
@@ -27,16 +42,151 @@ func initLogger() {
 }
 
 func main() {
-
 	initLogger()
 
-	CarManager = data.NewManager()
-	Router = server.NewRouter(addr)
+	// Without a command the API is served, flags included.
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	if command == "serve" {
+		serve(cfg, args)
+		return
+	}
+	err = admin(context.Background(), cfg, command, args, os.Stdout)
+	if errors.Is(err, errUsage) {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("Failed running %s: %v", command, err)
+	}
+}
+
+/*
+serve runs the API until the server fails or the process is interrupted.
+With a snapshot directory it starts from the latest snapshot, or the one
+named by -restore, and saves a new one when it stops, so the cars outlive
+the process.
+*/
+func serve(cfg config.Config, args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	restore := flags.String("restore", "", "name of the snapshot to load at startup, the latest by default")
+	flags.Parse(args)
 
-	Router.AddHandler("/cars", carsHandler) // GET
-	Router.AddHandler("/car", carHandler)   // POST && GET && PUT
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	if err := Router.Serve(); err != nil {
+	var rates *exchange.Rates
+	if cfg.ExchangeRatesFile != "" {
+		var err error
+		rates, err = exchange.Load(cfg.ExchangeRatesFile)
+		if err != nil {
+			log.Fatalf("Failed loading exchange rates: %v", err)
+		}
+	}
+
+	cars, vocabulary, err := newManager(cfg)
+	if err != nil {
+		log.Fatalf("Failed creating store: %v", err)
+	}
+
+	handlerOptions := []api.Option{
+		api.WithExchangeRates(rates),
+		api.WithVocabulary(vocabulary),
+		api.WithReservationHold(cfg.ReservationHold),
+		api.WithIdempotencyTTL(idempotencyTTL),
+	}
+	if cfg.MaxBodySize > 0 {
+		handlerOptions = append(handlerOptions, api.WithMaxBodySize(cfg.MaxBodySize))
+	}
+	switch cfg.Validation {
+	case config.ValidateRequests:
+		handlerOptions = append(handlerOptions, api.WithRequestValidation())
+	case config.ValidateResponses:
+		handlerOptions = append(handlerOptions, api.WithResponseValidation())
+	}
+	var snapshots *snapshot.Store
+	if cfg.SnapshotDir != "" {
+		snapshots, err = snapshot.NewStore(cfg.SnapshotDir, clock.System, snapshot.WithRetention(cfg.SnapshotKeep))
+		if err != nil {
+			log.Fatalf("Failed opening snapshot directory: %v", err)
+		}
+		handlerOptions = append(handlerOptions, api.WithSnapshots(snapshots), api.WithAdminToken(cfg.AdminToken))
+		if cfg.AdminToken == "" {
+			slog.Warn("CARS_ADMIN_TOKEN is not set, the snapshot endpoints are disabled")
+		}
+	}
+	handler := api.NewHandler(cars, slog.Default(), clock.System, handlerOptions...)
+
+	if snapshots == nil && *restore != "" {
+		log.Fatalf("Cannot restore snapshot '%s': CARS_SNAPSHOT_DIR is not set", *restore)
+	}
+	if snapshots != nil {
+		name := *restore
+		if name == "" || name == latestSnapshot {
+			if name, err = latest(snapshots); err != nil {
+				log.Fatalf("Failed listing snapshots: %v", err)
+			}
+		}
+		if name != "" {
+			if err := handler.RestoreSnapshot(ctx, name); err != nil {
+				log.Fatalf("Failed restoring snapshot: %v", err)
+			}
+		}
+		if cfg.SnapshotInterval > 0 {
+			go handler.SaveSnapshots(ctx, cfg.SnapshotInterval)
+		}
+	}
+
+	go handler.SweepReservations(ctx, reservationSweepInterval)
+	go handler.PurgeTrash(ctx, cfg.TrashRetention, trashPurgeInterval)
+
+	router := server.NewRouter(addr)
+	handler.Register(router)
+
+	err = router.Serve(ctx)
+	if snapshots != nil {
+		// Saved even when the server failed, the cars are still good.
+		if _, err := handler.SaveSnapshot(context.Background()); err != nil {
+			log.Fatalf("Failed saving snapshot: %v", err)
+		}
+	}
+	if err != nil {
 		log.Fatalf("Server failed during execution: %v", err)
 	}
 }
+
+// newManager creates an empty store checking cars against the configured
+// rules and vocabulary.
+func newManager(cfg config.Config) (data.Manager, *vocab.Registry, error) {
+	var rules *car.Rules
+	if cfg.RulesFile != "" {
+		var err error
+		rules, err = car.LoadRules(cfg.RulesFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error loading validation rules: %w", err)
+		}
+	}
+
+	vocabulary := vocab.NewRegistry()
+	if cfg.VocabularyFile != "" {
+		var err error
+		vocabulary, err = vocab.Load(cfg.VocabularyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error loading vocabulary: %w", err)
+		}
+	}
+
+	managerOptions := []data.Option{data.WithRules(rules), data.WithVocabulary(vocabulary)}
+	if cfg.StoreShards > 1 {
+		return data.NewShardedManager(cfg.StoreShards, managerOptions...), vocabulary, nil
+	}
+	return data.NewManager(managerOptions...), vocabulary, nil
+}
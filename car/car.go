@@ -0,0 +1,77 @@
+package car
+
+import (
+	"strings"
+	"time"
+)
+
+/*
+Record is a car of the inventory. Its struct tags also make its schema in
+the OpenAPI document of the API, doc tags describing the fields.
+*/
+type Record struct {
+	ID          string       `json:"id,omitempty" doc:"Unique ID of the car, generated when missing on creation"`
+	Make        string       `json:"make"`
+	Model       string       `json:"model"`
+	Category    string       `json:"category"`
+	Package     string       `json:"package"`
+	Color       string       `json:"color"`
+	Year        int          `json:"year"`
+	Mileage     int          `json:"mileage"`
+	MileageUnit DistanceUnit `json:"mileage_unit,omitempty" doc:"Unit of the mileage, miles when missing"`
+	Price       Money        `json:"price"`
+	VIN         string       `json:"vin,omitempty" doc:"ISO 3779 vehicle identification number, unique when present"`
+	Status      Status       `json:"status,omitempty" doc:"Sale status of the car, available when missing on creation"`
+}
+
+func (c Record) Validate() error {
+	if c.ID == "" {
+		return ErrorFieldMissing{"ID"}
+	}
+	if c.Make == "" {
+		return ErrorFieldMissing{"Make"}
+	}
+	if c.Model == "" {
+		return ErrorFieldMissing{"Model"}
+	}
+	if c.Category == "" {
+		return ErrorFieldMissing{"Category"}
+	}
+	if c.Package == "" {
+		return ErrorFieldMissing{"Package"}
+	}
+	if c.Color == "" {
+		return ErrorFieldMissing{"Color"}
+	}
+	if c.Year < 1900 || c.Year > time.Now().Year() {
+		return ErrorFieldInvalid{"Year", c.Year}
+	}
+	if c.Mileage < 0 {
+		return ErrorFieldInvalid{"Mileage", c.Mileage}
+	}
+	if c.MileageUnit != "" && !c.MileageUnit.Valid() {
+		return ErrorFieldInvalid{"MileageUnit", c.MileageUnit}
+	}
+	if c.Price.Amount <= 0 {
+		return ErrorFieldInvalid{"Price", c.Price}
+	}
+	if _, ok := CurrencyExponent(c.Price.Currency); !ok {
+		return ErrorFieldInvalid{"Currency", c.Price.Currency}
+	}
+	if c.Status != "" && !c.Status.Valid() {
+		return ErrorFieldInvalid{"Status", c.Status}
+	}
+	if c.VIN != "" {
+		info, ok := DecodeVIN(c.VIN)
+		if !ok {
+			return ErrorFieldInvalid{"VIN", c.VIN}
+		}
+		if info.Make != "" && !strings.EqualFold(info.Make, strings.TrimSpace(c.Make)) {
+			return ErrorFieldInvalid{"Make", c.Make}
+		}
+		if !info.MatchesYear(c.Year) {
+			return ErrorFieldInvalid{"Year", c.Year}
+		}
+	}
+	return nil
+}
@@ -16,7 +16,7 @@ func TestCar_Validate(t *testing.T) {
 		Color:    "Blue",
 		Year:     currentYear,
 		Mileage:  1000,
-		Price:    10000,
+		Price:    NewMoney(10000, "USD"),
 	}
 
 	missingIDRecord := validRecord
@@ -47,10 +47,39 @@ func TestCar_Validate(t *testing.T) {
 	negativeMileageRecord.Mileage = -1
 
 	zeroPriceRecord := validRecord
-	zeroPriceRecord.Price = 0
+	zeroPriceRecord.Price = Money{Amount: 0, Currency: "USD"}
 
 	negativePriceRecord := validRecord
-	negativePriceRecord.Price = -1
+	negativePriceRecord.Price = Money{Amount: -1, Currency: "USD"}
+
+	unknownCurrencyRecord := validRecord
+	unknownCurrencyRecord.Price = Money{Amount: 100, Currency: "XYZ"}
+
+	kilometersRecord := validRecord
+	kilometersRecord.MileageUnit = Kilometers
+
+	invalidUnitRecord := validRecord
+	invalidUnitRecord.MileageUnit = "furlong"
+
+	soldRecord := validRecord
+	soldRecord.Status = StatusSold
+
+	invalidStatusRecord := validRecord
+	invalidStatusRecord.Status = "stolen"
+
+	validVINRecord := validRecord
+	validVINRecord.Make = "Honda"
+	validVINRecord.Year = 2003
+	validVINRecord.VIN = "1HGCM82633A004352"
+
+	invalidVINRecord := validVINRecord
+	invalidVINRecord.VIN = "1HGCM82643A004352"
+
+	vinMakeMismatchRecord := validVINRecord
+	vinMakeMismatchRecord.Make = "Toyota"
+
+	vinYearMismatchRecord := validVINRecord
+	vinYearMismatchRecord.Year = 2004
 
 	tests := []struct {
 		name    string
@@ -113,12 +142,60 @@ func TestCar_Validate(t *testing.T) {
 		{
 			name:    "zero Price",
 			record:  zeroPriceRecord,
-			wantErr: ErrorFieldInvalid{"Price", 0},
+			wantErr: ErrorFieldInvalid{"Price", Money{0, "USD"}},
 		},
 		{
 			name:    "negative Price",
 			record:  negativePriceRecord,
-			wantErr: ErrorFieldInvalid{"Price", -1},
+			wantErr: ErrorFieldInvalid{"Price", Money{-1, "USD"}},
+		},
+		{
+			name:    "unknown currency",
+			record:  unknownCurrencyRecord,
+			wantErr: ErrorFieldInvalid{"Currency", "XYZ"},
+		},
+		// Mileage unit scenarios
+		{
+			name:    "mileage in kilometers",
+			record:  kilometersRecord,
+			wantErr: nil,
+		},
+		{
+			name:    "invalid mileage unit",
+			record:  invalidUnitRecord,
+			wantErr: ErrorFieldInvalid{"MileageUnit", DistanceUnit("furlong")},
+		},
+		// Status scenarios
+		{
+			name:    "sold status",
+			record:  soldRecord,
+			wantErr: nil,
+		},
+		{
+			name:    "invalid status",
+			record:  invalidStatusRecord,
+			wantErr: ErrorFieldInvalid{"Status", Status("stolen")},
+		},
+		// VIN scenarios
+		{
+			name:    "valid VIN",
+			record:  validVINRecord,
+			wantErr: nil,
+		},
+		{
+			name:    "invalid VIN check digit",
+			record:  invalidVINRecord,
+			wantErr: ErrorFieldInvalid{"VIN", "1HGCM82643A004352"},
+		},
+		{
+			name:    "VIN make mismatch",
+			record:  vinMakeMismatchRecord,
+			wantErr: ErrorFieldInvalid{"Make", "Toyota"},
+		},
+		{
+			name:    "VIN year mismatch",
+			record:  vinYearMismatchRecord,
+			wantErr: ErrorFieldInvalid{"Year", 2004},
 		},
 	}
 
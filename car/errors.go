@@ -20,3 +20,12 @@ type ErrorFieldInvalid struct {
 func (e ErrorFieldInvalid) Error() string {
 	return fmt.Sprintf("car field '%s' invalid value: '%v'", e.Field, e.Value)
 }
+
+type ErrorInvalidRule struct {
+	Index  int
+	Reason string
+}
+
+func (e ErrorInvalidRule) Error() string {
+	return fmt.Sprintf("validation rule %d invalid: %s", e.Index, e.Reason)
+}
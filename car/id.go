@@ -0,0 +1,70 @@
+package car
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+var (
+	idMu     sync.Mutex
+	lastIDMs int64
+	lastSeq  uint16
+)
+
+/*
+NewID returns a random UUIDv7 (RFC 9562) to be used as a record ID. The
+leading 48 bits hold the unix time in milliseconds and the following 12
+bits a counter, so IDs minted by one process sort in creation order.
+*/
+func NewID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("car: unable to read random bytes: " + err.Error())
+	}
+
+	ms, seq := nextIDTime()
+
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	b[6] = 0x70 | byte(seq>>8)&0x0f
+	b[7] = byte(seq)
+	b[8] = 0x80 | b[8]&0x3f
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
+
+// nextIDTime returns the timestamp and counter of the next ID, borrowing
+// from the next millisecond when the counter overflows.
+func nextIDTime() (int64, uint16) {
+	idMu.Lock()
+	defer idMu.Unlock()
+
+	ms := time.Now().UnixMilli()
+	if ms > lastIDMs {
+		lastIDMs = ms
+		lastSeq = 0
+	} else {
+		lastSeq++
+		if lastSeq > 0x0fff {
+			lastIDMs++
+			lastSeq = 0
+		}
+	}
+	return lastIDMs, lastSeq
+}
@@ -0,0 +1,22 @@
+package car
+
+import (
+	"regexp"
+	"testing"
+)
+
+var uuidV7Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewID(t *testing.T) {
+	previous := NewID()
+	for i := 0; i < 10000; i++ {
+		id := NewID()
+		if !uuidV7Pattern.MatchString(id) {
+			t.Fatalf("unexpected ID format, expected UUIDv7, got: %s", id)
+		}
+		if id <= previous {
+			t.Fatalf("expected IDs to increase, got '%s' after '%s'", id, previous)
+		}
+		previous = id
+	}
+}
@@ -0,0 +1,67 @@
+package car
+
+type DistanceUnit string
+
+const (
+	Kilometers DistanceUnit = "km"
+	Miles      DistanceUnit = "mi"
+)
+
+// A mile is exactly 1609.344 meters.
+const millimetersPerMile = 1609344
+
+func (u DistanceUnit) Valid() bool {
+	return u == Kilometers || u == Miles
+}
+
+// OdometerUnit returns the unit of Mileage, records without one are in
+// miles.
+func (c Record) OdometerUnit() DistanceUnit {
+	if c.MileageUnit == "" {
+		return Miles
+	}
+	return c.MileageUnit
+}
+
+// MileageIn returns Mileage converted to unit, rounded to the nearest
+// whole unit.
+func (c Record) MileageIn(unit DistanceUnit) int {
+	return ConvertDistance(c.Mileage, c.OdometerUnit(), unit)
+}
+
+// WithMileageIn returns a copy of the record with its odometer in unit.
+func (c Record) WithMileageIn(unit DistanceUnit) Record {
+	c.Mileage = c.MileageIn(unit)
+	c.MileageUnit = unit
+	return c
+}
+
+// ConvertDistance converts a distance between units, rounding to the
+// nearest whole unit.
+func ConvertDistance(distance int, from, to DistanceUnit) int {
+	if from == to {
+		return distance
+	}
+	return divRound(Meters(distance, from)*1000, unitMillimeters(to))
+}
+
+// Meters returns a distance in meters, the common unit used to compare
+// odometers recorded in different units.
+func Meters(distance int, unit DistanceUnit) int {
+	return divRound(distance*unitMillimeters(unit), 1000)
+}
+
+func unitMillimeters(unit DistanceUnit) int {
+	if unit == Kilometers {
+		return 1000000
+	}
+	return millimetersPerMile
+}
+
+// divRound divides rounding half away from zero.
+func divRound(a, b int) int {
+	if (a < 0) != (b < 0) {
+		return (a - b/2) / b
+	}
+	return (a + b/2) / b
+}
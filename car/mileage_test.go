@@ -0,0 +1,40 @@
+package car
+
+import "testing"
+
+func TestConvertDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		distance int
+		from     DistanceUnit
+		to       DistanceUnit
+		want     int
+	}{
+		{"same unit", 1234, Kilometers, Kilometers, 1234},
+		{"miles to kilometers", 1000, Miles, Kilometers, 1609},
+		{"kilometers to miles", 1000, Kilometers, Miles, 621},
+		{"rounds half up", 1, Kilometers, Miles, 1},
+		{"zero", 0, Miles, Kilometers, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConvertDistance(tt.distance, tt.from, tt.to); got != tt.want {
+				t.Fatalf("unexpected distance, wanted: %d, got: %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRecord_WithMileageIn(t *testing.T) {
+	record := Record{Mileage: 100000}
+
+	if record.OdometerUnit() != Miles {
+		t.Fatalf("expected records without unit to be in miles, got: %s", record.OdometerUnit())
+	}
+
+	got := record.WithMileageIn(Kilometers)
+	if got.Mileage != 160934 || got.MileageUnit != Kilometers {
+		t.Fatalf("unexpected conversion, got: %d %s", got.Mileage, got.MileageUnit)
+	}
+}
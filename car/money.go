@@ -0,0 +1,167 @@
+package car
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCurrency is assumed for prices given as a bare integer.
+const DefaultCurrency = "USD"
+
+// currencyExponents maps ISO 4217 currency codes to the number of digits
+// of their minor unit.
+var currencyExponents = map[string]int{
+	"AED": 2, "ARS": 2, "AUD": 2, "BGN": 2, "BHD": 3, "BRL": 2, "CAD": 2,
+	"CHF": 2, "CLP": 0, "CNY": 2, "COP": 2, "CZK": 2, "DKK": 2, "EGP": 2,
+	"EUR": 2, "GBP": 2, "HKD": 2, "HUF": 2, "IDR": 2, "ILS": 2, "INR": 2,
+	"ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0, "KWD": 3, "MXN": 2, "MYR": 2,
+	"NOK": 2, "NZD": 2, "OMR": 3, "PEN": 2, "PHP": 2, "PLN": 2, "QAR": 2,
+	"RON": 2, "RUB": 2, "SAR": 2, "SEK": 2, "SGD": 2, "THB": 2, "TND": 3,
+	"TRY": 2, "TWD": 2, "UAH": 2, "USD": 2, "VND": 0, "ZAR": 2,
+}
+
+// CurrencyExponent returns the number of minor unit digits of an ISO 4217
+// currency code, ok is false for unknown codes.
+func CurrencyExponent(code string) (exponent int, ok bool) {
+	exponent, ok = currencyExponents[code]
+	return exponent, ok
+}
+
+/*
+Money is an amount in the minor unit of its currency, cents for USD. It
+is encoded in JSON as {"amount": 1999, "currency": "USD"} and also
+decodes a bare integer as whole units of DefaultCurrency, so 20 becomes
+{"amount": 2000, "currency": "USD"}. Objects with other fields are refused.
+*/
+type Money struct {
+	Amount   int    `json:"amount" doc:"Amount in minor units of the currency"`
+	Currency string `json:"currency,omitempty" doc:"ISO 4217 currency code, USD when missing"`
+}
+
+// PriceChange records the price a car was listed at from a point in time.
+type PriceChange struct {
+	Price Money     `json:"price"`
+	At    time.Time `json:"at"`
+}
+
+// NewMoney returns whole units of currency, it panics on unknown codes
+// and on amounts that do not fit in minor units.
+func NewMoney(units int, currency string) Money {
+	money, err := ParseUnits(strconv.Itoa(units), currency)
+	if err != nil {
+		panic(fmt.Sprintf("car: %v", err))
+	}
+	return money
+}
+
+func (m Money) String() string {
+	exponent, ok := CurrencyExponent(m.Currency)
+	if !ok || exponent == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+	}
+
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	divisor := 1
+	for i := 0; i < exponent; i++ {
+		divisor *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/divisor, exponent, amount%divisor, m.Currency)
+}
+
+/*
+Units returns the amount in whole units of the currency as a decimal
+number, without trailing zeros, so 199900 USD is "1999" and 199950 USD
+"1999.5". Amounts of unknown currencies are left as they are.
+*/
+func (m Money) Units() string {
+	exponent, _ := CurrencyExponent(m.Currency)
+	digits := strconv.Itoa(m.Amount)
+	sign := ""
+	if m.Amount < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	units, fraction := digits[:len(digits)-exponent], strings.TrimRight(digits[len(digits)-exponent:], "0")
+	if fraction == "" {
+		return sign + units
+	}
+	return sign + units + "." + fraction
+}
+
+type ErrorInvalidUnits struct {
+	Units    string
+	Currency string
+}
+
+func (e ErrorInvalidUnits) Error() string {
+	return fmt.Sprintf("'%s' is not an amount of %s", e.Units, e.Currency)
+}
+
+// ParseUnits reads an amount of whole units of currency as Units writes
+// it. It may not be more precise than the minor unit of the currency.
+func ParseUnits(units, currency string) (Money, error) {
+	exponent, ok := CurrencyExponent(currency)
+	if !ok {
+		return Money{}, ErrorInvalidUnits{units, currency}
+	}
+	whole, fraction, point := strings.Cut(units, ".")
+	if !isDigits(strings.TrimPrefix(whole, "-")) || point && !isDigits(fraction) || len(fraction) > exponent {
+		return Money{}, ErrorInvalidUnits{units, currency}
+	}
+	amount, err := strconv.Atoi(whole + fraction + strings.Repeat("0", exponent-len(fraction)))
+	if err != nil {
+		return Money{}, ErrorInvalidUnits{units, currency}
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, digit := range s {
+		if digit < '0' || digit > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func (m *Money) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] != '{' {
+		var units int
+		if err := json.Unmarshal(b, &units); err != nil {
+			return err
+		}
+		money, err := ParseUnits(strconv.Itoa(units), DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		*m = money
+		return nil
+	}
+
+	type plain Money
+	var p plain
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&p); err != nil {
+		return err
+	}
+	if p.Currency == "" {
+		p.Currency = DefaultCurrency
+	}
+	p.Currency = strings.ToUpper(p.Currency)
+	*m = Money(p)
+	return nil
+}
@@ -0,0 +1,135 @@
+package car
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMoney_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		json      string
+		wantMoney Money
+		wantErr   bool
+	}{
+		{
+			name:      "legacy integer in default currency",
+			json:      `20000`,
+			wantMoney: Money{Amount: 2000000, Currency: "USD"},
+		},
+		{
+			name:      "amount and currency",
+			json:      `{"amount": 1999, "currency": "eur"}`,
+			wantMoney: Money{Amount: 1999, Currency: "EUR"},
+		},
+		{
+			name:      "amount without currency",
+			json:      `{"amount": 1999}`,
+			wantMoney: Money{Amount: 1999, Currency: "USD"},
+		},
+		{
+			name:    "fractional legacy price",
+			json:    `19.99`,
+			wantErr: true,
+		},
+		{
+			name:    "misspelled field",
+			json:    `{"amount": 1999, "curency": "EUR"}`,
+			wantErr: true,
+		},
+		{
+			name:    "string",
+			json:    `"19.99"`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.json), &got)
+			if err != nil {
+				if !tt.wantErr {
+					t.Fatalf("unexpected error, wanted success, got: %v", err)
+				}
+				return
+			} else if tt.wantErr {
+				t.Fatalf("unexpected success, decoded: %v", got)
+			}
+			if got != tt.wantMoney {
+				t.Fatalf("unexpected money, wanted: %v, got: %v", tt.wantMoney, got)
+			}
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{1999, "USD"}, "19.99 USD"},
+		{Money{-5, "EUR"}, "-0.05 EUR"},
+		{Money{1500, "JPY"}, "1500 JPY"},
+		{Money{12345, "KWD"}, "12.345 KWD"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("unexpected string, wanted: %s, got: %s", tt.want, got)
+		}
+	}
+}
+
+func TestMoney_Units(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{2000000, "USD"}, "20000"},
+		{Money{1999, "USD"}, "19.99"},
+		{Money{1950, "EUR"}, "19.5"},
+		{Money{-5, "EUR"}, "-0.05"},
+		{Money{1500, "JPY"}, "1500"},
+		{Money{12345, "KWD"}, "12.345"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Units(); got != tt.want {
+			t.Errorf("unexpected units, wanted: %s, got: %s", tt.want, got)
+		}
+		if got, err := ParseUnits(tt.want, tt.money.Currency); err != nil || got != tt.money {
+			t.Errorf("unexpected money parsing %s, wanted: %v, got: %v, error: %v", tt.want, tt.money, got, err)
+		}
+	}
+}
+
+func TestParseUnits_Invalid(t *testing.T) {
+	for _, tt := range []struct {
+		units    string
+		currency string
+	}{
+		{"", "USD"},
+		{"19.999", "USD"},
+		{"19.5", "JPY"},
+		{"19.", "USD"},
+		{".5", "USD"},
+		{"1e3", "USD"},
+		{"+5", "USD"},
+		{"19", "XXX"},
+		{"184467440737095517", "USD"},
+	} {
+		if got, err := ParseUnits(tt.units, tt.currency); err == nil {
+			t.Errorf("unexpected success parsing '%s' %s: %v", tt.units, tt.currency, got)
+		}
+	}
+}
+
+func TestMoney_UnmarshalJSON_Overflow(t *testing.T) {
+	var got Money
+	err := json.Unmarshal([]byte(`184467440737095517`), &got)
+	want := ErrorInvalidUnits{Units: "184467440737095517", Currency: "USD"}
+	if err != want {
+		t.Fatalf("unexpected error, wanted: %v, got: %v (decoded %v)", want, err, got)
+	}
+}
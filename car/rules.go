@@ -0,0 +1,196 @@
+package car
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ruleField describes a record field rules can refer to by its JSON name.
+type ruleField struct {
+	name    string
+	numeric bool
+	value   func(Record) any
+}
+
+var ruleFields = map[string]ruleField{
+	"id":           {"ID", false, func(c Record) any { return c.ID }},
+	"make":         {"Make", false, func(c Record) any { return c.Make }},
+	"model":        {"Model", false, func(c Record) any { return c.Model }},
+	"category":     {"Category", false, func(c Record) any { return c.Category }},
+	"package":      {"Package", false, func(c Record) any { return c.Package }},
+	"color":        {"Color", false, func(c Record) any { return c.Color }},
+	"year":         {"Year", true, func(c Record) any { return c.Year }},
+	"mileage":      {"Mileage", true, func(c Record) any { return c.Mileage }},
+	"mileage_km":   {"MileageKm", true, func(c Record) any { return c.MileageIn(Kilometers) }},
+	"mileage_mi":   {"MileageMi", true, func(c Record) any { return c.MileageIn(Miles) }},
+	"mileage_unit": {"MileageUnit", false, func(c Record) any { return string(c.OdometerUnit()) }},
+	"price":        {"Price", true, func(c Record) any { return c.Price.Amount }},
+	"currency":     {"Currency", false, func(c Record) any { return c.Price.Currency }},
+	"vin":          {"VIN", false, func(c Record) any { return c.VIN }},
+}
+
+/*
+Constraint restricts the value of a field, unset constraints always hold.
+Min and Max apply to numeric fields, Enum and Pattern to any field.
+Currency scopes a constraint on price to prices in that currency, whose
+minor units its Min and Max are in. Bounding price requires it.
+*/
+type Constraint struct {
+	Min      *int     `json:"min,omitempty"`
+	Max      *int     `json:"max,omitempty"`
+	Enum     []string `json:"enum,omitempty"`
+	Pattern  string   `json:"pattern,omitempty"`
+	Currency string   `json:"currency,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// Condition makes a rule apply only to records whose field satisfies the
+// constraint, so rules can relate two fields.
+type Condition struct {
+	Field string `json:"field"`
+	Constraint
+}
+
+type Rule struct {
+	Field    string `json:"field"`
+	Required bool   `json:"required,omitempty"`
+	Constraint
+	When *Condition `json:"when,omitempty"`
+}
+
+/*
+Rules are declarative validation constraints, loaded per dealership on
+top of the checks of Record.Validate. Fields are named as in the JSON
+encoding of a record, plus mileage_km and mileage_mi which convert the
+odometer. Prices are in minor units of the currency of the constraint,
+cars priced in another currency are not bounded. For example:
+
+	{"rules": [
+		{"field": "year", "min": 2005},
+		{"field": "color", "enum": ["Black", "White", "Silver"]},
+		{"field": "mileage_km", "max": 100000, "when": {"field": "year", "max": 2015}},
+		{"field": "price", "min": 500000, "currency": "USD"}
+	]}
+*/
+type Rules struct {
+	Rules []Rule `json:"rules"`
+}
+
+func LoadRules(path string) (*Rules, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRules(b)
+}
+
+func ParseRules(b []byte) (*Rules, error) {
+	var rules Rules
+	if err := json.Unmarshal(b, &rules); err != nil {
+		return nil, err
+	}
+
+	for i := range rules.Rules {
+		rule := &rules.Rules[i]
+		if err := rule.Constraint.compile(i, rule.Field); err != nil {
+			return nil, err
+		}
+		// Numeric fields are never empty, a zero is bounded with min.
+		if rule.Required && ruleFields[rule.Field].numeric {
+			return nil, ErrorInvalidRule{i, fmt.Sprintf("required does not apply to field '%s', set min instead", rule.Field)}
+		}
+		if rule.When != nil {
+			if err := rule.When.Constraint.compile(i, rule.When.Field); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &rules, nil
+}
+
+func (c *Constraint) compile(index int, field string) error {
+	f, ok := ruleFields[field]
+	if !ok {
+		return ErrorInvalidRule{index, fmt.Sprintf("unknown field '%s'", field)}
+	}
+	if !f.numeric && (c.Min != nil || c.Max != nil) {
+		return ErrorInvalidRule{index, fmt.Sprintf("min and max do not apply to field '%s'", field)}
+	}
+	c.Currency = strings.ToUpper(c.Currency)
+	if field != "price" && c.Currency != "" {
+		return ErrorInvalidRule{index, fmt.Sprintf("currency does not apply to field '%s'", field)}
+	}
+	if field == "price" && (c.Min != nil || c.Max != nil) && c.Currency == "" {
+		return ErrorInvalidRule{index, "min and max of field 'price' need a currency"}
+	}
+	if _, ok := CurrencyExponent(c.Currency); c.Currency != "" && !ok {
+		return ErrorInvalidRule{index, fmt.Sprintf("unknown currency '%s'", c.Currency)}
+	}
+	if c.Pattern != "" {
+		pattern, err := regexp.Compile(c.Pattern)
+		if err != nil {
+			return ErrorInvalidRule{index, err.Error()}
+		}
+		c.pattern = pattern
+	}
+	return nil
+}
+
+// scopes reports whether c applies to record, which it does unless it is
+// scoped to another currency.
+func (c Constraint) scopes(record Record) bool {
+	return c.Currency == "" || c.Currency == record.Price.Currency
+}
+
+func (c Constraint) holds(value any) bool {
+	if n, ok := value.(int); ok {
+		if c.Min != nil && n < *c.Min {
+			return false
+		}
+		if c.Max != nil && n > *c.Max {
+			return false
+		}
+	}
+	text := fmt.Sprint(value)
+	if len(c.Enum) > 0 && !slices.Contains(c.Enum, text) {
+		return false
+	}
+	if c.pattern != nil && !c.pattern.MatchString(text) {
+		return false
+	}
+	return true
+}
+
+// Validate checks record against every rule, a nil Rules accepts anything.
+func (r *Rules) Validate(record Record) error {
+	if r == nil {
+		return nil
+	}
+
+	for _, rule := range r.Rules {
+		if rule.When != nil && !(rule.When.scopes(record) && rule.When.holds(ruleFields[rule.When.Field].value(record))) {
+			continue
+		}
+		if !rule.scopes(record) {
+			continue
+		}
+
+		field := ruleFields[rule.Field]
+		value := field.value(record)
+		if value == "" {
+			if rule.Required {
+				return ErrorFieldMissing{field.name}
+			}
+			continue
+		}
+		if !rule.holds(value) {
+			return ErrorFieldInvalid{field.name, value}
+		}
+	}
+	return nil
+}
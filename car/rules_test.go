@@ -0,0 +1,193 @@
+package car
+
+import "testing"
+
+func TestRules_Validate(t *testing.T) {
+	rules, err := ParseRules([]byte(`{"rules": [
+		{"field": "year", "min": 2005},
+		{"field": "color", "enum": ["Blue", "Black"]},
+		{"field": "vin", "pattern": "^[A-Z0-9]{17}$"},
+		{"field": "package", "required": true},
+		{"field": "mileage_km", "max": 100000, "when": {"field": "year", "max": 2015}},
+		{"field": "price", "min": 500000, "currency": "usd"},
+		{"field": "package", "enum": ["Premium"], "when": {"field": "price", "min": 3000000, "currency": "EUR"}}
+	]}`))
+	if err != nil {
+		t.Fatalf("unexpected error parsing rules: %v", err)
+	}
+
+	validRecord := Record{
+		ID:       "123",
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     2012,
+		Mileage:  50000,
+		Price:    NewMoney(10000, "USD"),
+	}
+
+	tooOldRecord := validRecord
+	tooOldRecord.Year = 2004
+
+	colorRecord := validRecord
+	colorRecord.Color = "Pink"
+
+	highMileageRecord := validRecord
+	highMileageRecord.Mileage = 70000
+
+	recentHighMileageRecord := highMileageRecord
+	recentHighMileageRecord.Year = 2020
+
+	noPackageRecord := validRecord
+	noPackageRecord.Package = ""
+
+	cheapRecord := validRecord
+	cheapRecord.Price = NewMoney(4000, "USD")
+
+	cheapYenRecord := validRecord
+	cheapYenRecord.Price = NewMoney(4000, "JPY")
+
+	dearEuroRecord := validRecord
+	dearEuroRecord.Price = NewMoney(40000, "EUR")
+
+	dearYenRecord := validRecord
+	dearYenRecord.Price = NewMoney(4000000, "JPY")
+
+	tests := []struct {
+		name    string
+		record  Record
+		wantErr error
+	}{
+		{
+			name:    "valid record",
+			record:  validRecord,
+			wantErr: nil,
+		},
+		{
+			name:    "year below range",
+			record:  tooOldRecord,
+			wantErr: ErrorFieldInvalid{"Year", 2004},
+		},
+		{
+			name:    "color not allowed",
+			record:  colorRecord,
+			wantErr: ErrorFieldInvalid{"Color", "Pink"},
+		},
+		{
+			name:    "cross-field rule applies",
+			record:  highMileageRecord,
+			wantErr: ErrorFieldInvalid{"MileageKm", 112654},
+		},
+		{
+			name:    "cross-field rule does not apply",
+			record:  recentHighMileageRecord,
+			wantErr: nil,
+		},
+		{
+			name:    "required field",
+			record:  noPackageRecord,
+			wantErr: ErrorFieldMissing{"Package"},
+		},
+		{
+			name:    "price below range in the currency of the rule",
+			record:  cheapRecord,
+			wantErr: ErrorFieldInvalid{"Price", 400000},
+		},
+		{
+			name:    "price in another currency is not bounded",
+			record:  cheapYenRecord,
+			wantErr: nil,
+		},
+		{
+			name:    "price condition in its currency",
+			record:  dearEuroRecord,
+			wantErr: ErrorFieldInvalid{"Package", "Standard"},
+		},
+		{
+			name:    "price condition in another currency",
+			record:  dearYenRecord,
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rules.Validate(tt.record)
+			if err != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("unexpected error, wanted success, got: %v", err)
+				}
+				if err.Error() != tt.wantErr.Error() {
+					t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+				}
+			} else if tt.wantErr != nil {
+				t.Fatalf("unexpected success, expected error: %s", tt.wantErr.Error())
+			}
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr error
+	}{
+		{
+			name:    "unknown field",
+			json:    `{"rules": [{"field": "wheels", "min": 4}]}`,
+			wantErr: ErrorInvalidRule{0, "unknown field 'wheels'"},
+		},
+		{
+			name:    "range on text field",
+			json:    `{"rules": [{"field": "year", "min": 2000}, {"field": "color", "max": 3}]}`,
+			wantErr: ErrorInvalidRule{1, "min and max do not apply to field 'color'"},
+		},
+		{
+			name:    "required numeric field",
+			json:    `{"rules": [{"field": "package", "required": true}, {"field": "price", "required": true}]}`,
+			wantErr: ErrorInvalidRule{1, "required does not apply to field 'price', set min instead"},
+		},
+		{
+			name:    "unknown condition field",
+			json:    `{"rules": [{"field": "year", "min": 2000, "when": {"field": "owner"}}]}`,
+			wantErr: ErrorInvalidRule{0, "unknown field 'owner'"},
+		},
+		{
+			name:    "price bound without currency",
+			json:    `{"rules": [{"field": "price", "max": 100000}]}`,
+			wantErr: ErrorInvalidRule{0, "min and max of field 'price' need a currency"},
+		},
+		{
+			name:    "price condition without currency",
+			json:    `{"rules": [{"field": "color", "enum": ["Red"], "when": {"field": "price", "min": 100000}}]}`,
+			wantErr: ErrorInvalidRule{0, "min and max of field 'price' need a currency"},
+		},
+		{
+			name:    "currency on another field",
+			json:    `{"rules": [{"field": "year", "min": 2000, "currency": "USD"}]}`,
+			wantErr: ErrorInvalidRule{0, "currency does not apply to field 'year'"},
+		},
+		{
+			name:    "unknown currency",
+			json:    `{"rules": [{"field": "price", "min": 100, "currency": "XXX"}]}`,
+			wantErr: ErrorInvalidRule{0, "unknown currency 'XXX'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRules([]byte(tt.json))
+			if err == nil || err.Error() != tt.wantErr.Error() {
+				t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	var nilRules *Rules
+	if err := nilRules.Validate(Record{}); err != nil {
+		t.Fatalf("expected nil rules to accept any record, got: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package car
+
+import (
+	"slices"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusAvailable Status = "available"
+	StatusReserved  Status = "reserved"
+	StatusSold      Status = "sold"
+	StatusInService Status = "in_service"
+)
+
+// transitions lists the statuses each status can move to, a sold car
+// stays sold.
+var transitions = map[Status][]Status{
+	StatusAvailable: {StatusReserved, StatusSold, StatusInService},
+	StatusReserved:  {StatusAvailable, StatusSold},
+	StatusInService: {StatusAvailable},
+	StatusSold:      {},
+}
+
+func (s Status) Valid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+func (s Status) CanTransitionTo(to Status) bool {
+	return slices.Contains(transitions[s], to)
+}
+
+// StatusChange records a car entering a status. From is empty for the
+// status a car was added with.
+type StatusChange struct {
+	From Status    `json:"from,omitempty"`
+	To   Status    `json:"to"`
+	At   time.Time `json:"at"`
+}
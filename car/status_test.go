@@ -0,0 +1,29 @@
+package car
+
+import "testing"
+
+func TestStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from Status
+		to   Status
+		want bool
+	}{
+		{StatusAvailable, StatusReserved, true},
+		{StatusAvailable, StatusSold, true},
+		{StatusAvailable, StatusInService, true},
+		{StatusReserved, StatusSold, true},
+		{StatusReserved, StatusAvailable, true},
+		{StatusReserved, StatusInService, false},
+		{StatusInService, StatusAvailable, true},
+		{StatusInService, StatusSold, false},
+		{StatusSold, StatusAvailable, false},
+		{StatusAvailable, StatusAvailable, false},
+		{StatusAvailable, "stolen", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("unexpected transition from %s to %s, wanted: %v, got: %v", tt.from, tt.to, tt.want, got)
+		}
+	}
+}
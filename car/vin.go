@@ -0,0 +1,114 @@
+package car
+
+import "strings"
+
+const vinLength = 17
+
+// vinWeights are the ISO 3779 position weights, the check digit at
+// position 9 weighs nothing.
+var vinWeights = [vinLength]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// vinYearCodes lists the model year characters of position 10, repeating
+// every 30 years from 1980.
+const vinYearCodes = "ABCDEFGHJKLMNPRSTVWXY123456789"
+
+// vinManufacturers maps world manufacturer identifiers to makes. It only
+// covers common manufacturers, unknown identifiers are not cross-checked.
+var vinManufacturers = map[string]string{
+	"1HG": "Honda", "2HG": "Honda", "5FN": "Honda", "JHM": "Honda",
+	"JHL": "Honda", "19X": "Honda",
+	"4T1": "Toyota", "4T3": "Toyota", "5TD": "Toyota", "5TF": "Toyota",
+	"2T1": "Toyota", "JTD": "Toyota", "JTE": "Toyota", "JTM": "Toyota",
+	"JTN": "Toyota", "JT2": "Toyota",
+	"1FA": "Ford", "1FM": "Ford", "1FT": "Ford", "3FA": "Ford",
+	"WF0": "Ford",
+	"1G1": "Chevrolet", "1GC": "Chevrolet", "2G1": "Chevrolet",
+	"1N4": "Nissan", "1N6": "Nissan", "3N1": "Nissan", "JN1": "Nissan",
+	"JN8": "Nissan",
+	"JM1": "Mazda", "JM3": "Mazda",
+	"JF1": "Subaru", "JF2": "Subaru", "4S3": "Subaru", "4S4": "Subaru",
+	"KNA": "Kia", "KND": "Kia", "5XY": "Kia",
+	"KMH": "Hyundai", "5NP": "Hyundai",
+	"WBA": "BMW", "WBS": "BMW", "5UX": "BMW",
+	"WDD": "Mercedes-Benz", "WDB": "Mercedes-Benz", "4JG": "Mercedes-Benz",
+	"WVW": "Volkswagen", "WVG": "Volkswagen", "3VW": "Volkswagen",
+	"WAU": "Audi", "WA1": "Audi",
+	"WP0": "Porsche", "WP1": "Porsche",
+	"YV1": "Volvo", "YV4": "Volvo",
+	"5YJ": "Tesla", "7SA": "Tesla",
+	"SAL": "Land Rover", "SAJ": "Jaguar",
+	"ZFF": "Ferrari", "ZAR": "Alfa Romeo",
+}
+
+// VINInfo is the information decoded offline from a VIN.
+type VINInfo struct {
+	WMI string
+	// Make is empty when the manufacturer identifier is not known.
+	Make string
+	// YearCode is the model year character, it stands for one year in
+	// every 30 year cycle since 1980.
+	YearCode byte
+}
+
+// MatchesYear reports whether year is one of the model years the VIN year
+// code stands for. Years before 1980 predate the 17 character VIN.
+func (v VINInfo) MatchesYear(year int) bool {
+	if year < 1980 {
+		return false
+	}
+	return vinYearCodes[(year-1980)%len(vinYearCodes)] == v.YearCode
+}
+
+// vinValue transliterates a VIN character, I, O and Q are never used.
+func vinValue(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'A' && c <= 'H':
+		return int(c-'A') + 1, true
+	case c >= 'J' && c <= 'N':
+		return int(c-'J') + 1, true
+	case c == 'P':
+		return 7, true
+	case c == 'R':
+		return 9, true
+	case c >= 'S' && c <= 'Z':
+		return int(c-'S') + 2, true
+	}
+	return 0, false
+}
+
+// ValidVIN reports whether vin is a 17 character VIN with a correct
+// ISO 3779 check digit.
+func ValidVIN(vin string) bool {
+	if len(vin) != vinLength {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < vinLength; i++ {
+		value, ok := vinValue(vin[i])
+		if !ok {
+			return false
+		}
+		sum += value * vinWeights[i]
+	}
+
+	check := byte('0' + sum%11)
+	if sum%11 == 10 {
+		check = 'X'
+	}
+	return vin[8] == check && strings.IndexByte(vinYearCodes, vin[9]) >= 0
+}
+
+// DecodeVIN decodes the manufacturer and model year of a valid VIN.
+func DecodeVIN(vin string) (VINInfo, bool) {
+	if !ValidVIN(vin) {
+		return VINInfo{}, false
+	}
+	return VINInfo{
+		WMI:      vin[:3],
+		Make:     vinManufacturers[vin[:3]],
+		YearCode: vin[9],
+	}, true
+}
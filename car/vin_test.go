@@ -0,0 +1,51 @@
+package car
+
+import "testing"
+
+func TestValidVIN(t *testing.T) {
+	tests := []struct {
+		name string
+		vin  string
+		want bool
+	}{
+		{"valid", "1HGCM82633A004352", true},
+		{"valid with X check digit", "1M8GDM9AXKP042788", true},
+		{"wrong check digit", "1HGCM82643A004352", false},
+		{"too short", "1HGCM82633A00435", false},
+		{"forbidden letter", "1HGCM82633A0O4352", false},
+		{"lowercase", "1hgcm82633a004352", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidVIN(tt.vin); got != tt.want {
+				t.Fatalf("unexpected result for '%s', wanted: %v, got: %v", tt.vin, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDecodeVIN(t *testing.T) {
+	info, ok := DecodeVIN("1HGCM82633A004352")
+	if !ok {
+		t.Fatal("expected VIN to decode")
+	}
+	if info.WMI != "1HG" || info.Make != "Honda" {
+		t.Fatalf("unexpected manufacturer, got: %+v", info)
+	}
+	for _, year := range []int{2003, 2033} {
+		if !info.MatchesYear(year) {
+			t.Fatalf("expected year code '%c' to match %d", info.YearCode, year)
+		}
+	}
+	for _, year := range []int{1973, 2002, 2004} {
+		if info.MatchesYear(year) {
+			t.Fatalf("expected year code '%c' not to match %d", info.YearCode, year)
+		}
+	}
+
+	info, ok = DecodeVIN("1M8GDM9AXKP042788")
+	if !ok || info.Make != "" {
+		t.Fatalf("expected unknown manufacturer to decode without make, got: %+v", info)
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Package client calls the Cars API from Go. Failed requests are retried
+with exponential backoff when the server or the network is at fault, and
+the errors of the API are returned as the car and data errors that caused
+them.
+*/
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+const (
+	defaultRetries = 3
+	defaultBackoff = 100 * time.Millisecond
+	defaultTimeout = 30 * time.Second
+)
+
+// apiPrefix is the version of the API the client speaks, the one whose
+// cars are car.Record as is.
+const apiPrefix = "/v2"
+
+// Client is a client of one Cars API server, safe for concurrent use.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	retries int
+	backoff time.Duration
+	token   string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sends the requests with httpClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.http = httpClient
+	}
+}
+
+// WithRetries retries a failed request up to retries times, waiting
+// backoff before the first retry and twice as long before each next one.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// WithToken authenticates every request with token as a bearer token.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// New returns a Client of the API served at baseURL, e.g.
+// "http://localhost:8080".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Timeout: defaultTimeout},
+		retries: defaultRetries,
+		backoff: defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Add stores a car and returns it as stored, with its ID minted by the
+// server when empty. Retries are sent with the same Idempotency-Key so the
+// car is added once.
+func (c *Client) Add(ctx context.Context, record car.Record) (car.Record, error) {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return car.Record{}, err
+	}
+
+	header := http.Header{"Idempotency-Key": {car.NewID()}}
+	var stored car.Record
+	err = c.do(ctx, http.MethodPost, "/cars", nil, header, body, http.StatusCreated, &stored)
+	return stored, err
+}
+
+// Get returns the car with the given ID.
+func (c *Client) Get(ctx context.Context, carID string) (car.Record, error) {
+	var record car.Record
+	err := c.do(ctx, http.MethodGet, carPath(carID), nil, nil, nil, http.StatusOK, &record)
+	return record, err
+}
+
+// List returns the cars matching q, a page of them when q has a Limit.
+// Mileages are in q.MileageUnit when set.
+func (c *Client) List(ctx context.Context, q data.Query) ([]car.Record, error) {
+	var records []car.Record
+	err := c.do(ctx, http.MethodGet, "/cars", queryValues(q), nil, nil, http.StatusOK, &records)
+	return records, err
+}
+
+// Update replaces a stored car with record.
+func (c *Client) Update(ctx context.Context, record car.Record) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPut, carPath(record.ID), nil, nil, body, http.StatusAccepted, nil)
+}
+
+// Delete moves a car to the trash.
+func (c *Client) Delete(ctx context.Context, carID string) error {
+	return c.do(ctx, http.MethodDelete, carPath(carID), nil, nil, nil, http.StatusNoContent, nil)
+}
+
+func carPath(carID string) string {
+	return "/cars/" + url.PathEscape(carID)
+}
+
+/*
+do sends a request until it gets a response other than a server error or
+runs out of retries, and decodes a response with the wanted status into
+out when not nil. Other responses are returned as errors.
+*/
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body []byte, want int, out any) error {
+	target := c.baseURL + apiPrefix + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	wait := c.backoff
+	for attempt := 0; ; attempt++ {
+		status, response, err := c.send(ctx, method, target, header, body)
+		if err == nil && status == want {
+			if out == nil {
+				return nil
+			}
+			if err := json.Unmarshal(response, out); err != nil {
+				return fmt.Errorf("error decoding response: %w", err)
+			}
+			return nil
+		}
+		if err == nil {
+			err = responseError(status, response)
+		}
+		if attempt == c.retries || !retryable(status) || ctx.Err() != nil {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, target string, header http.Header, body []byte) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	response, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, response, nil
+}
+
+// retryable reports whether a request answered with status, zero when it
+// got no response, may succeed when sent again.
+func retryable(status int) bool {
+	switch status {
+	case 0:
+		// The request did not get a response.
+		return true
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// queryValues encodes q as the query parameters of GET /cars.
+func queryValues(q data.Query) url.Values {
+	values := url.Values{}
+	set := func(name, value string) {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+	setInt := func(name string, n *int) {
+		if n != nil {
+			values.Set(name, strconv.Itoa(*n))
+		}
+	}
+	// The API reads price bounds in whole units of the currency, which
+	// it requires along with them.
+	currency := strings.ToUpper(q.Currency)
+	setPrice := func(name string, amount *int) {
+		if amount != nil {
+			values.Set(name, car.Money{Amount: *amount, Currency: currency}.Units())
+		}
+	}
+
+	set("make", q.Make)
+	set("model", q.Model)
+	set("category", q.Category)
+	set("color", q.Color)
+	set("currency", q.Currency)
+	set("status", string(q.Status))
+	setInt("min_year", q.Year.Min)
+	setInt("max_year", q.Year.Max)
+	setPrice("min_price", q.Price.Min)
+	setPrice("max_price", q.Price.Max)
+	setInt("min_mileage", q.Mileage.Min)
+	setInt("max_mileage", q.Mileage.Max)
+	set("units", string(q.MileageUnit))
+	if !q.PriceDroppedSince.IsZero() {
+		values.Set("price_dropped_since", q.PriceDroppedSince.Format(time.RFC3339Nano))
+	}
+	set("sort", string(q.SortBy))
+	if q.Desc {
+		values.Set("order", "desc")
+	}
+	if q.Offset > 0 {
+		values.Set("offset", strconv.Itoa(q.Offset))
+	}
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return values
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YoungOak/GoAPI/api"
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/servertest"
+)
+
+var testRecord = car.Record{
+	ID:       "123",
+	Make:     "Toyota",
+	Model:    "Camry",
+	Category: "Sedan",
+	Package:  "Standard",
+	Color:    "Blue",
+	Year:     2020,
+	Mileage:  1000,
+	Price:    car.NewMoney(10000, "USD"),
+	Status:   car.StatusAvailable,
+}
+
+// newTestServer serves a new API, through wrap when not nil.
+func newTestServer(t *testing.T, wrap func(http.Handler) http.Handler) *httptest.Server {
+	router := servertest.NewRouter()
+	api.NewHandler(data.NewManager(), slog.Default(), clock.System, api.WithResponseValidation()).Register(router)
+
+	var handler http.Handler = router
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	c := New(newTestServer(t, nil).URL)
+
+	added, err := c.Add(ctx, testRecord)
+	if err != nil {
+		t.Fatalf("unexpected error adding: %v", err)
+	}
+	if !reflect.DeepEqual(added, testRecord) {
+		t.Fatalf("unexpected added car, wanted: %v, got: %v", testRecord, added)
+	}
+
+	updated := testRecord
+	updated.Color = "Red"
+	if err := c.Update(ctx, updated); err != nil {
+		t.Fatalf("unexpected error updating: %v", err)
+	}
+	if got, err := c.Get(ctx, testRecord.ID); err != nil || got.Color != "Red" {
+		t.Fatalf("expected updated car, got: %v, %v", got, err)
+	}
+
+	if err := c.Delete(ctx, testRecord.ID); err != nil {
+		t.Fatalf("unexpected error deleting: %v", err)
+	}
+	_, err = c.Get(ctx, testRecord.ID)
+	if want := (data.ErrorRecordNotFound{ID: testRecord.ID}); err != want {
+		t.Fatalf("unexpected error getting deleted car, wanted: %v, got: %v", want, err)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	ctx := context.Background()
+	c := New(newTestServer(t, nil).URL)
+	_, _ = c.Add(ctx, testRecord)
+
+	missing := testRecord
+	missing.ID, missing.Make = "", ""
+	invalid := testRecord
+	invalid.ID, invalid.Year = "", 1800
+	sold := testRecord
+	sold.Status = car.StatusSold
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"missing field", func() error { _, err := c.Add(ctx, missing); return err }, car.ErrorFieldMissing{Field: "Make"}},
+		{"invalid field", func() error { _, err := c.Add(ctx, invalid); return err }, car.ErrorFieldInvalid{Field: "Year", Value: "1800"}},
+		{"duplicate", func() error { _, err := c.Add(ctx, testRecord); return err }, data.ErrorAlreadyExists{ID: "123"}},
+		{"not found", func() error { return c.Delete(ctx, "456") }, data.ErrorRecordNotFound{ID: "456"}},
+		{"bad query", func() error { _, err := c.List(ctx, data.Query{SortBy: "color"}); return err },
+			ErrorUnexpectedStatus{http.StatusBadRequest, "query parameter 'sort' invalid value: 'color'"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !reflect.DeepEqual(err, tt.want) {
+				t.Fatalf("unexpected error, wanted: %#v, got: %#v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestClient_Iterate(t *testing.T) {
+	ctx := context.Background()
+	c := New(newTestServer(t, nil).URL)
+
+	var ids []string
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		record := testRecord
+		record.ID = id
+		if _, err := c.Add(ctx, record); err != nil {
+			t.Fatalf("unexpected error adding: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	// Price bounds are minor units, sent to the API in whole units.
+	price, below := testRecord.Price.Amount, testRecord.Price.Amount-50
+	tests := []struct {
+		name  string
+		query data.Query
+		want  []string
+	}{
+		{"all", data.Query{}, ids},
+		{"price range", data.Query{Currency: "USD", Price: data.Range{Min: &below, Max: &price}}, ids},
+		{"cheaper", data.Query{Currency: "USD", Price: data.Range{Max: &below}}, nil},
+		{"offset", data.Query{Offset: 1}, ids[1:]},
+		{"limit", data.Query{Offset: 1, Limit: 3}, ids[1:4]},
+		{"descending", data.Query{Desc: true, Limit: 1}, []string{"5"}},
+		{"no match", data.Query{Make: "Honda"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			it := c.Iterate(tt.query, 2)
+			for it.Next(ctx) {
+				got = append(got, it.Record().ID)
+			}
+			if err := it.Err(); err != nil {
+				t.Fatalf("unexpected error iterating: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("unexpected cars, wanted: %v, got: %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := c.List(ctx, data.Query{Price: data.Range{Max: &price}}); err != (data.ErrorCurrencyMissing{}) {
+		t.Fatalf("unexpected error listing prices without currency, wanted: %v, got: %v", data.ErrorCurrencyMissing{}, err)
+	}
+}
+
+// flaky fails the first failures requests with 503, recording the
+// idempotency keys of every request.
+func flaky(failures int, keys *[]string) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			*keys = append(*keys, r.Header.Get("Idempotency-Key"))
+			fail := len(*keys) <= failures
+			mu.Unlock()
+			if fail {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	ctx := context.Background()
+
+	var keys []string
+	c := New(newTestServer(t, flaky(2, &keys)).URL, WithRetries(2, time.Millisecond))
+	if _, err := c.Add(ctx, testRecord); err != nil {
+		t.Fatalf("unexpected error adding: %v", err)
+	}
+	if len(keys) != 3 || keys[0] == "" || keys[1] != keys[0] || keys[2] != keys[0] {
+		t.Fatalf("expected three attempts with the same idempotency key, got: %v", keys)
+	}
+
+	keys = nil
+	c = New(newTestServer(t, flaky(3, &keys)).URL, WithRetries(2, time.Millisecond))
+	_, err := c.Get(ctx, testRecord.ID)
+	if _, unavailable := err.(ErrorUnexpectedStatus); !unavailable || len(keys) != 3 {
+		t.Fatalf("expected to give up after three attempts, got %d attempts: %v", len(keys), err)
+	}
+
+	keys = nil
+	c = New(newTestServer(t, flaky(0, &keys)).URL, WithRetries(2, time.Millisecond))
+	if _, err := c.Get(ctx, "456"); len(keys) != 1 {
+		t.Fatalf("expected client errors not to be retried, got %d attempts: %v", len(keys), err)
+	}
+}
+
+func TestClient_CancelledDuringBackoff(t *testing.T) {
+	var keys []string
+	c := New(newTestServer(t, flaky(1, &keys)).URL, WithRetries(1, time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.Get(ctx, testRecord.ID); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline to be exceeded, got: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+// ErrorUnexpectedStatus is an error response of the API that does not map
+// to a car or data error.
+type ErrorUnexpectedStatus struct {
+	Status  int
+	Message string
+}
+
+func (e ErrorUnexpectedStatus) Error() string {
+	return fmt.Sprintf("unexpected response %d %s: %s", e.Status, http.StatusText(e.Status), e.Message)
+}
+
+// apiErrors parse the messages of the API back to the errors they were
+// written from. Values of car.ErrorFieldInvalid are parsed as strings.
+var apiErrors = []struct {
+	pattern *regexp.Regexp
+	parse   func(m []string) error
+}{
+	{regexp.MustCompile(`^car field '(.*)' missing$`), func(m []string) error {
+		return car.ErrorFieldMissing{Field: m[1]}
+	}},
+	{regexp.MustCompile(`^car field '(.*?)' invalid value: '(.*)'$`), func(m []string) error {
+		return car.ErrorFieldInvalid{Field: m[1], Value: m[2]}
+	}},
+	{regexp.MustCompile(`^entry with ID '(.*)' alrady exists$`), func(m []string) error {
+		return data.ErrorAlreadyExists{ID: m[1]}
+	}},
+	{regexp.MustCompile(`^no record in store with ID: '(.*)'$`), func(m []string) error {
+		return data.ErrorRecordNotFound{ID: m[1]}
+	}},
+	{regexp.MustCompile(`^entry with VIN '(.*)' already exists$`), func(m []string) error {
+		return data.ErrorVINAlreadyExists{VIN: m[1]}
+	}},
+	{regexp.MustCompile(`^no record in store with VIN: '(.*)'$`), func(m []string) error {
+		return data.ErrorVINNotFound{VIN: m[1]}
+	}},
+	{regexp.MustCompile(`^price bounds need a currency$`), func(m []string) error {
+		return data.ErrorCurrencyMissing{}
+	}},
+	{regexp.MustCompile(`^car '(.*?)' must be added as '.*?', not '(.*)'$`), func(m []string) error {
+		return data.ErrorInitialStatus{ID: m[1], Status: car.Status(m[2])}
+	}},
+	{regexp.MustCompile(`^car '(.*?)' cannot go from '(.*?)' to '(.*)'$`), func(m []string) error {
+		return data.ErrorIllegalTransition{ID: m[1], From: car.Status(m[2]), To: car.Status(m[3])}
+	}},
+}
+
+// responseError returns the error of an error response.
+func responseError(status int, body []byte) error {
+	message := strings.TrimSpace(string(body))
+	if status < http.StatusInternalServerError {
+		for _, apiError := range apiErrors {
+			if m := apiError.pattern.FindStringSubmatch(message); m != nil {
+				return apiError.parse(m)
+			}
+		}
+	}
+	return ErrorUnexpectedStatus{status, message}
+}
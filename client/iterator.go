@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+/*
+Iterator walks the cars matching a query one page at a time:
+
+	it := c.Iterate(data.Query{Make: "Toyota"}, 100)
+	for it.Next(ctx) {
+		record := it.Record()
+		...
+	}
+	if err := it.Err(); err != nil {
+		...
+	}
+
+Pages are fetched by offset, so cars added or deleted during the walk
+may shift the pages and be skipped or seen twice.
+*/
+type Iterator struct {
+	client *Client
+	query  data.Query
+	// remaining is the number of cars left to return, negative when
+	// unlimited.
+	remaining int
+	page      []car.Record
+	index     int
+	record    car.Record
+	lastPage  bool
+	err       error
+}
+
+// Iterate returns an Iterator over the cars matching q, from q.Offset and
+// up to q.Limit cars when set, fetching pageSize cars per request.
+func (c *Client) Iterate(q data.Query, pageSize int) *Iterator {
+	if pageSize < 1 {
+		pageSize = 1
+	}
+	it := &Iterator{client: c, query: q, remaining: -1}
+	if q.Limit > 0 {
+		it.remaining = q.Limit
+		pageSize = min(pageSize, q.Limit)
+	}
+	it.query.Limit = pageSize
+	return it
+}
+
+// Next moves to the next car, fetching the next page when needed. It
+// returns false once every car was returned or on error.
+func (it *Iterator) Next(ctx context.Context) bool {
+	if it.err != nil || it.remaining == 0 {
+		return false
+	}
+	if it.index == len(it.page) {
+		if it.lastPage {
+			return false
+		}
+		page, err := it.client.List(ctx, it.query)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.index = page, 0
+		it.query.Offset += len(page)
+		it.lastPage = len(page) < it.query.Limit
+		if len(page) == 0 {
+			return false
+		}
+	}
+
+	it.record = it.page[it.index]
+	it.index++
+	if it.remaining > 0 {
+		it.remaining--
+	}
+	return true
+}
+
+// Record returns the car Next moved to.
+func (it *Iterator) Record() car.Record {
+	return it.record
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
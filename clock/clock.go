@@ -0,0 +1,88 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time, so code depending on it can be tested without
+// waiting for real time to pass.
+type Clock interface {
+	Now() time.Time
+	// After sends the time on the returned channel once d has passed.
+	After(d time.Duration) <-chan time.Time
+}
+
+type system struct{}
+
+func (system) Now() time.Time {
+	return time.Now()
+}
+
+func (system) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// System is the wall clock.
+var System Clock = system{}
+
+// Fake is a Clock that only moves when told to.
+type Fake struct {
+	now     time.Time
+	waiters []waiter
+	mu      *sync.Mutex
+}
+
+// waiter is a channel returned by After, sent to once the clock reaches at.
+type waiter struct {
+	at time.Time
+	c  chan time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now, mu: &sync.Mutex{}}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := make(chan time.Time, 1)
+	if d <= 0 {
+		c <- f.now
+		return c
+	}
+	f.waiters = append(f.waiters, waiter{f.now.Add(d), c})
+	return c
+}
+
+// Advance moves the clock forward by d, firing the channels of After that
+// are due.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+
+	pending := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.at.After(f.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.c <- f.now
+	}
+	f.waiters = pending
+}
+
+// Waiters returns how many channels of After have not fired yet, so tests
+// can wait for code to start waiting before advancing the clock.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
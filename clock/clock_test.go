@@ -0,0 +1,50 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+
+	if got := fake.Now(); !got.Equal(start) {
+		t.Fatalf("unexpected time, wanted: %v, got: %v", start, got)
+	}
+
+	fake.Advance(48 * time.Hour)
+	want := start.Add(48 * time.Hour)
+	if got := fake.Now(); !got.Equal(want) {
+		t.Fatalf("unexpected time after advance, wanted: %v, got: %v", want, got)
+	}
+}
+
+func TestFake_After(t *testing.T) {
+	fake := NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	soon, later := fake.After(time.Minute), fake.After(time.Hour)
+	if got := fake.Waiters(); got != 2 {
+		t.Fatalf("unexpected waiters, wanted: 2, got: %d", got)
+	}
+
+	fake.Advance(time.Minute)
+	select {
+	case at := <-soon:
+		if !at.Equal(fake.Now()) {
+			t.Fatalf("unexpected time sent, wanted: %v, got: %v", fake.Now(), at)
+		}
+	default:
+		t.Fatal("expected the channel due to fire")
+	}
+	select {
+	case <-later:
+		t.Fatal("unexpected fire before the duration passed")
+	default:
+	}
+	if got := fake.Waiters(); got != 1 {
+		t.Fatalf("unexpected waiters, wanted: 1, got: %d", got)
+	}
+
+	fake.Advance(time.Hour)
+	<-later
+}
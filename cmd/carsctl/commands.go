@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/client"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/carfile"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+
+	// pageSize is the number of cars fetched per request when listing.
+	pageSize = 100
+)
+
+// cli runs the commands of carsctl against one server.
+type cli struct {
+	client *client.Client
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	format string
+}
+
+func newCLI(cfg config, stdin io.Reader, stdout, stderr io.Writer, format string) *cli {
+	return &cli{
+		client: client.New(cfg.URL, client.WithToken(cfg.Token)),
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		format: format,
+	}
+}
+
+// flags returns the flag set of a command taking the given arguments.
+func (c *cli) flags(command, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: carsctl %s %s\n", command, arguments)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// args parses the arguments of a command and checks there are at least
+// atLeast and at most atMost of them, any number when atMost is negative.
+func (c *cli) args(flags *flag.FlagSet, args []string, atLeast, atMost int) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() < atLeast || atMost >= 0 && flags.NArg() > atMost {
+		flags.Usage()
+		return nil, errUsage
+	}
+	return flags.Args(), nil
+}
+
+func (c *cli) list(ctx context.Context, args []string) error {
+	flags := c.flags("list", "[filters]")
+	var q data.Query
+	flags.StringVar(&q.Make, "make", "", "only cars of this make")
+	flags.StringVar(&q.Model, "model", "", "only cars of this model")
+	flags.StringVar(&q.Category, "category", "", "only cars of this category")
+	flags.StringVar(&q.Color, "color", "", "only cars of this color")
+	flags.StringVar(&q.Currency, "currency", "", "only cars priced in this currency")
+	status := flags.String("status", "", "only cars in this status")
+	minYear := flags.Int("min-year", 0, "oldest model year")
+	maxYear := flags.Int("max-year", 0, "newest model year")
+	minPrice := flags.Int("min-price", 0, "lowest price, in minor units of -currency")
+	maxPrice := flags.Int("max-price", 0, "highest price, in minor units of -currency")
+	sort := flags.String("sort", "", "sort by id, year, price or mileage")
+	flags.BoolVar(&q.Desc, "desc", false, "sort in descending order")
+	flags.IntVar(&q.Offset, "offset", 0, "skip this many cars")
+	flags.IntVar(&q.Limit, "limit", 0, "list at most this many cars")
+	if _, err := c.args(flags, args, 0, 0); err != nil {
+		return err
+	}
+
+	q.Status = car.Status(*status)
+	q.SortBy = data.SortField(*sort)
+	// Only flags given on the command line bound the ranges.
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "min-year":
+			q.Year.Min = minYear
+		case "max-year":
+			q.Year.Max = maxYear
+		case "min-price":
+			q.Price.Min = minPrice
+		case "max-price":
+			q.Price.Max = maxPrice
+		}
+	})
+
+	records, err := c.all(ctx, q)
+	if err != nil {
+		return err
+	}
+	return c.printRecords(records)
+}
+
+func (c *cli) get(ctx context.Context, args []string) error {
+	args, err := c.args(c.flags("get", "<id>"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	record, err := c.client.Get(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return c.printRecord(record)
+}
+
+func (c *cli) add(ctx context.Context, args []string) error {
+	record, err := c.readRecord(c.flags("add", "[file]"), args)
+	if err != nil {
+		return err
+	}
+
+	added, err := c.client.Add(ctx, record)
+	if err != nil {
+		return err
+	}
+	return c.printRecord(added)
+}
+
+func (c *cli) update(ctx context.Context, args []string) error {
+	record, err := c.readRecord(c.flags("update", "[file]"), args)
+	if err != nil {
+		return err
+	}
+
+	if err := c.client.Update(ctx, record); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "updated %s\n", record.ID)
+	return nil
+}
+
+func (c *cli) delete(ctx context.Context, args []string) error {
+	args, err := c.args(c.flags("delete", "<id>..."), args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range args {
+		if err := c.client.Delete(ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stdout, "deleted %s\n", id)
+	}
+	return nil
+}
+
+// importFile adds every car of a file, going on past the cars the server
+// rejects.
+func (c *cli) importFile(ctx context.Context, args []string) error {
+	args, err := c.args(c.flags("import", "<file>"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	records, err := carfile.Read(args[0])
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for i, record := range records {
+		added, err := c.client.Add(ctx, record)
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			fmt.Fprintf(c.stderr, "car %d '%s': %v\n", i+1, record.ID, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(c.stdout, "added %s\n", added.ID)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d cars not imported", failed, len(records))
+	}
+	return nil
+}
+
+func (c *cli) exportFile(ctx context.Context, args []string) error {
+	args, err := c.args(c.flags("export", "<file>"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	records, err := c.all(ctx, data.Query{})
+	if err != nil {
+		return err
+	}
+	if err := carfile.Write(args[0], records); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "exported %d cars to %s\n", len(records), args[0])
+	return nil
+}
+
+// all fetches every car matching q, page by page.
+func (c *cli) all(ctx context.Context, q data.Query) ([]car.Record, error) {
+	records := []car.Record{}
+	it := c.client.Iterate(q, pageSize)
+	for it.Next(ctx) {
+		records = append(records, it.Record())
+	}
+	return records, it.Err()
+}
+
+// readRecord decodes the JSON car of the file argument, or of stdin when
+// there is none or it is "-".
+func (c *cli) readRecord(flags *flag.FlagSet, args []string) (car.Record, error) {
+	args, err := c.args(flags, args, 0, 1)
+	if err != nil {
+		return car.Record{}, err
+	}
+
+	in := c.stdin
+	if len(args) == 1 && args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return car.Record{}, err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	var record car.Record
+	if err := json.NewDecoder(in).Decode(&record); err != nil {
+		return car.Record{}, fmt.Errorf("error decoding car: %w", err)
+	}
+	return record, nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// printRecord prints a car as a JSON object or a table of one row.
+func (c *cli) printRecord(record car.Record) error {
+	if c.format == formatJSON {
+		return writeJSON(c.stdout, record)
+	}
+	return c.printRecords([]car.Record{record})
+}
+
+func (c *cli) printRecords(records []car.Record) error {
+	if c.format == formatJSON {
+		return writeJSON(c.stdout, records)
+	}
+
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tMAKE\tMODEL\tYEAR\tCOLOR\tMILEAGE\tPRICE\tSTATUS")
+	for _, r := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%d %s\t%s\t%s\n",
+			r.ID, r.Make, r.Model, r.Year, r.Color, r.Mileage, r.OdometerUnit(), r.Price, r.Status)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const defaultURL = "http://localhost:8080"
+
+// config tells carsctl which API to talk to and how to authenticate.
+type config struct {
+	URL   string `json:"url"`
+	Token string `json:"token"`
+}
+
+// defaultConfigPath is the config file read when none is given, it may
+// not exist.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "carsctl", "config.json")
+}
+
+/*
+loadConfig reads the JSON config file at path, then lets the CARS_URL and
+CARS_TOKEN environment variables override it. A missing file is only an
+error when the path was given explicitly.
+*/
+func loadConfig(path string, explicit bool) (config, error) {
+	cfg := config{URL: defaultURL}
+
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(raw, &cfg); err != nil {
+				return config{}, fmt.Errorf("invalid config file '%s': %w", path, err)
+			}
+		case explicit || !errors.Is(err, fs.ErrNotExist):
+			return config{}, err
+		}
+	}
+
+	if url := os.Getenv("CARS_URL"); url != "" {
+		cfg.URL = url
+	}
+	if token := os.Getenv("CARS_TOKEN"); token != "" {
+		cfg.Token = token
+	}
+	return cfg, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	_ = os.WriteFile(path, []byte(`{"url": "http://cars.example", "token": "from-file"}`), 0o600)
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	tests := []struct {
+		name     string
+		path     string
+		explicit bool
+		env      map[string]string
+		want     config
+		wantErr  bool
+	}{
+		{"defaults", missing, false, nil, config{URL: defaultURL}, false},
+		{"file", path, true, nil, config{URL: "http://cars.example", Token: "from-file"}, false},
+		{"env over file", path, true, map[string]string{"CARS_TOKEN": "from-env"}, config{URL: "http://cars.example", Token: "from-env"}, false},
+		{"env", missing, false, map[string]string{"CARS_URL": "http://env.example"}, config{URL: "http://env.example"}, false},
+		{"missing explicit file", missing, true, nil, config{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CARS_URL", "")
+			t.Setenv("CARS_TOKEN", "")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			got, err := loadConfig(tt.path, tt.explicit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("unexpected config, wanted: %+v, got: %+v", tt.want, got)
+			}
+		})
+	}
+}
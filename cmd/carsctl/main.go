@@ -0,0 +1,109 @@
+/*
+Command carsctl manages the inventory of a Cars API server.
+
+	carsctl [-config file] [-url url] [-o table|json] <command> [arguments]
+
+The server URL and token are read from the config file, a JSON object
+such as {"url": "http://localhost:8080", "token": "..."}, then from the
+CARS_URL and CARS_TOKEN environment variables, then from the flags.
+*/
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `Usage: carsctl [-config file] [-url url] [-o table|json] <command> [arguments]
+
+Commands:
+  list [filters]    list cars, see carsctl list -h for the filters
+  get <id>          show a car
+  add [file]        add the car of a JSON file, read from stdin when omitted
+  update [file]     replace a car with the one of a JSON file or stdin
+  delete <id>...    move cars to the trash
+  import <file>     add every car of a .csv or .json file
+  export <file>     write every car to a .csv or .json file
+
+Flags:
+`
+
+// errUsage is returned after printing the usage of a command.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	err := run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintf(os.Stderr, "carsctl: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run runs the carsctl command line args.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("carsctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	configPath := flags.String("config", "", "config `file`, defaults to "+defaultConfigPath())
+	url := flags.String("url", "", "`URL` of the server, overrides the config")
+	format := flags.String("o", formatTable, "output `format`, table or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *format != formatTable && *format != formatJSON {
+		fmt.Fprintf(stderr, "unknown output format '%s'\n", *format)
+		flags.Usage()
+		return errUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	path, explicit := *configPath, *configPath != ""
+	if !explicit {
+		path = defaultConfigPath()
+	}
+	cfg, err := loadConfig(path, explicit)
+	if err != nil {
+		return err
+	}
+	if *url != "" {
+		cfg.URL = *url
+	}
+
+	c := newCLI(cfg, stdin, stdout, stderr, *format)
+	command, commandArgs := flags.Arg(0), flags.Args()[1:]
+	switch command {
+	case "list":
+		return c.list(ctx, commandArgs)
+	case "get":
+		return c.get(ctx, commandArgs)
+	case "add":
+		return c.add(ctx, commandArgs)
+	case "update":
+		return c.update(ctx, commandArgs)
+	case "delete":
+		return c.delete(ctx, commandArgs)
+	case "import":
+		return c.importFile(ctx, commandArgs)
+	case "export":
+		return c.exportFile(ctx, commandArgs)
+	}
+	fmt.Fprintf(stderr, "unknown command '%s'\n", command)
+	flags.Usage()
+	return errUsage
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/api"
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/internal/carfile"
+	"github.com/YoungOak/GoAPI/internal/servertest"
+)
+
+var testRecords = []car.Record{
+	{
+		ID: "1", Make: "Toyota", Model: "Camry", Category: "Sedan", Package: "Standard", Color: "Blue",
+		Year: 2020, Mileage: 1000, Price: car.NewMoney(10000, "USD"), Status: car.StatusAvailable,
+	},
+	{
+		ID: "2", Make: "Honda", Model: "Civic, Type R", Category: "Hatchback", Package: "Sport", Color: "Red",
+		Year: 2003, Mileage: 500, MileageUnit: car.Kilometers, Price: car.NewMoney(30000, "EUR"), VIN: "1HGCM82633A004352",
+	},
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+
+	router := servertest.NewRouter()
+	api.NewHandler(data.NewManager(), slog.Default(), clock.System, api.WithResponseValidation()).Register(router)
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		router.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	t.Setenv("CARS_URL", server.URL)
+	t.Setenv("CARS_TOKEN", "secret")
+
+	// No config file, the server is taken from the environment.
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	carsctl := func(stdin string, args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		err := run(ctx, args, strings.NewReader(stdin), &stdout, &stderr)
+		return stdout.String(), err
+	}
+
+	body, _ := json.Marshal(testRecords[0])
+	if _, err := carsctl(string(body), "add"); err != nil {
+		t.Fatalf("unexpected error adding: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "cars.csv")
+	_ = carfile.Write(path, testRecords[1:])
+	if out, err := carsctl("", "import", path); err != nil || out != "added 2\n" {
+		t.Fatalf("unexpected import output: %q, %v", out, err)
+	}
+	if _, err := carsctl("", "import", path); err == nil {
+		t.Fatal("expected importing a duplicate to fail")
+	}
+
+	out, err := carsctl("", "-o", "json", "list", "-make", "Honda")
+	var listed []car.Record
+	if err != nil || json.Unmarshal([]byte(out), &listed) != nil || len(listed) != 1 || listed[0].ID != "2" {
+		t.Fatalf("unexpected list output: %s, %v", out, err)
+	}
+
+	out, err = carsctl("", "get", "1")
+	if err != nil || !strings.HasPrefix(out, "ID") || !strings.Contains(out, "10000.00 USD") {
+		t.Fatalf("unexpected get output: %s, %v", out, err)
+	}
+
+	exported := filepath.Join(t.TempDir(), "cars.json")
+	if _, err := carsctl("", "export", exported); err != nil {
+		t.Fatalf("unexpected error exporting: %v", err)
+	}
+	if got, _ := carfile.Read(exported); len(got) != 2 || !reflect.DeepEqual(got[0], testRecords[0]) {
+		t.Fatalf("unexpected exported cars: %v", got)
+	}
+
+	if out, err := carsctl("", "delete", "1", "2"); err != nil || out != "deleted 1\ndeleted 2\n" {
+		t.Fatalf("unexpected delete output: %q, %v", out, err)
+	}
+	if _, err := carsctl("", "get", "1"); err != (data.ErrorRecordNotFound{ID: "1"}) {
+		t.Fatalf("expected deleted car not to be found, got: %v", err)
+	}
+
+	if _, err := carsctl("", "get"); !errors.Is(err, errUsage) {
+		t.Fatalf("expected usage error, got: %v", err)
+	}
+	for _, token := range tokens {
+		if token != "Bearer secret" {
+			t.Fatalf("expected every request to carry the token, got: %q", token)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+// These tests are meant to be run with -race.
+
+const stressGoroutines = 64
+
+func stressRecord(id string) car.Record {
+	return car.Record{
+		ID:       id,
+		Make:     "Honda",
+		Model:    "Accord",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     2003,
+		Mileage:  0,
+		Price:    car.NewMoney(10000, "USD"),
+	}
+}
+
+// stress runs fn from many goroutines released at once.
+func stress(fn func(i int)) {
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < stressGoroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			fn(i)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+}
+
+func TestManager_ConcurrentAddSameID(t *testing.T) {
+	ctx := context.Background()
+
+	for round := 0; round < 20; round++ {
+		testManager := NewManager()
+
+		var mu sync.Mutex
+		added := map[string]int{}
+		stress(func(i int) {
+			record := stressRecord("123")
+			record.Color = fmt.Sprintf("Color %d", i)
+			if err := testManager.Add(ctx, record); err == nil {
+				mu.Lock()
+				added[record.Color]++
+				mu.Unlock()
+			} else if _, exists := err.(ErrorAlreadyExists); !exists {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+
+		if len(added) != 1 {
+			t.Fatalf("expected exactly one add to succeed, got: %v", added)
+		}
+		stored, _ := testManager.Get(ctx, "123")
+		if added[stored.Color] != 1 {
+			t.Fatalf("stored record %v is not the one that was added: %v", stored, added)
+		}
+		if got := mustQuery(t, testManager, Query{Make: "Honda"}); len(got) != 1 {
+			t.Fatalf("expected a single indexed record, got: %v", got)
+		}
+	}
+}
+
+func TestManager_ConcurrentAddSameVIN(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+
+	var mu sync.Mutex
+	succeeded := 0
+	stress(func(i int) {
+		record := stressRecord(fmt.Sprintf("%d", i))
+		record.VIN = "1HGCM82633A004352"
+		if err := testManager.Add(ctx, record); err == nil {
+			mu.Lock()
+			succeeded++
+			mu.Unlock()
+		}
+	})
+
+	if succeeded != 1 {
+		t.Fatalf("expected exactly one car to get the VIN, got: %d", succeeded)
+	}
+	if got := mustList(t, testManager); len(got) != 1 {
+		t.Fatalf("expected a single stored record, got: %v", got)
+	}
+}
+
+func TestManager_ConcurrentUpdates(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+	_ = testManager.Add(ctx, stressRecord("123"))
+
+	// Read-modify-write cycles in transactions never lose an increment.
+	const increments = 50
+	stress(func(i int) {
+		for n := 0; n < increments; n++ {
+			err := testManager.Tx(ctx, func(tx Tx) error {
+				record, err := tx.Get("123")
+				if err != nil {
+					return err
+				}
+				record.Mileage++
+				return tx.Update(record)
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+	})
+
+	record, _ := testManager.Get(ctx, "123")
+	if want := stressGoroutines * increments; record.Mileage != want {
+		t.Fatalf("lost updates, wanted mileage: %d, got: %d", want, record.Mileage)
+	}
+}
+
+func TestManager_ConcurrentUpdateAndDelete(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+	_ = testManager.Add(ctx, stressRecord("123"))
+
+	stress(func(i int) {
+		switch i % 4 {
+		case 0:
+			_ = testManager.Delete(ctx, "123")
+		case 1:
+			_, _ = testManager.Restore(ctx, "123")
+		case 2:
+			record := stressRecord("123")
+			record.Mileage = i
+			_ = testManager.Update(ctx, record)
+		default:
+			_, _ = testManager.Get(ctx, "123")
+			_, _ = testManager.Query(ctx, Query{Make: "Honda", SortBy: SortByMileage})
+		}
+	})
+
+	// The car is either stored or trashed, never both nor lost.
+	_, err := testManager.Get(ctx, "123")
+	stored := err == nil
+	trashed := len(mustTrash(t, testManager)) == 1
+	if stored == trashed {
+		t.Fatalf("expected car to be either stored or trashed, stored: %v, trashed: %v", stored, trashed)
+	}
+	if got := len(mustQuery(t, testManager, Query{Make: "Honda"})); stored && got != 1 || !stored && got != 0 {
+		t.Fatalf("indexes out of sync with the store, got %d indexed records", got)
+	}
+}
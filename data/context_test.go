@@ -0,0 +1,92 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// countdownContext is cancelled once its error was checked a given number
+// of times, to cancel long operations halfway through.
+type countdownContext struct {
+	context.Context
+	checks int
+}
+
+func (c *countdownContext) Err() error {
+	if c.checks == 0 {
+		return context.Canceled
+	}
+	c.checks--
+	return nil
+}
+
+func TestManager_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, tt := range []struct {
+		name    string
+		manager Manager
+	}{
+		{"single", NewManager()},
+		{"sharded", NewShardedManager(4)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.manager.Add(ctx, stressRecord("123")); err != context.Canceled {
+				t.Fatalf("expected add to be cancelled, got: %v", err)
+			}
+			if got := mustList(t, tt.manager); len(got) != 0 {
+				t.Fatalf("expected cancelled add not to store the car, got: %v", got)
+			}
+			if _, err := tt.manager.List(ctx); err != context.Canceled {
+				t.Fatalf("expected list to be cancelled, got: %v", err)
+			}
+			if _, err := tt.manager.Dump(ctx); err != context.Canceled {
+				t.Fatalf("expected dump to be cancelled, got: %v", err)
+			}
+			called := false
+			err := tt.manager.Tx(ctx, func(Tx) error {
+				called = true
+				return nil
+			})
+			if err != context.Canceled || called {
+				t.Fatalf("expected transaction not to run, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestManager_QueryCancelledHalfway(t *testing.T) {
+	testManager := NewManager()
+	for i := 0; i < 3*checkEvery; i++ {
+		record := stressRecord(fmt.Sprintf("%05d", i))
+		if i%2 == 0 {
+			record.Make = "Toyota"
+		}
+		if err := testManager.Add(context.Background(), record); err != nil {
+			t.Fatalf("unexpected error adding: %v", err)
+		}
+	}
+
+	// The first check is made before the query starts. A query on Make
+	// filters the cars of that make instead of walking every car.
+	for _, q := range []Query{{}, {Make: "Honda"}} {
+		ctx := &countdownContext{Context: context.Background(), checks: 2}
+		if got, err := testManager.Query(ctx, q); err != context.Canceled || got != nil {
+			t.Fatalf("expected query %+v to be cancelled, got %d records: %v", q, len(got), err)
+		}
+	}
+}
+
+func TestManager_DeadlineExceeded(t *testing.T) {
+	testManager := NewManager()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	if _, err := testManager.Get(ctx, "123"); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline to be exceeded, got: %v", err)
+	}
+}
@@ -0,0 +1,291 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/vocab"
+)
+
+/*
+Manager stores the cars. Every method fails with the error of its
+context once it is cancelled or past its deadline, long operations such
+as List and Query check it as they go.
+*/
+type Manager interface {
+	Add(ctx context.Context, record car.Record) error
+	Get(ctx context.Context, carID string) (car.Record, error)
+	GetByVIN(ctx context.Context, vin string) (car.Record, error)
+	List(ctx context.Context) ([]car.Record, error)
+	Query(ctx context.Context, q Query) ([]car.Record, error)
+	Update(ctx context.Context, record car.Record) error
+	Patch(ctx context.Context, carID string, fn func(car.Record) (car.Record, error)) (car.Record, error)
+	Transition(ctx context.Context, carID string, to car.Status) (car.Record, error)
+	StatusHistory(ctx context.Context, carID string) ([]car.StatusChange, error)
+	PriceHistory(ctx context.Context, carID string) ([]car.PriceChange, error)
+	Delete(ctx context.Context, carID string) error
+	Trash(ctx context.Context) ([]TrashedRecord, error)
+	Restore(ctx context.Context, carID string) (car.Record, error)
+	Purge(ctx context.Context, retention time.Duration) ([]string, error)
+	Dump(ctx context.Context) (State, error)
+	Load(ctx context.Context, state State) error
+	Tx(ctx context.Context, fn func(Tx) error) error
+}
+
+// checkEvery is how many records long operations visit between checks of
+// their context.
+const checkEvery = 1024
+
+type manager struct {
+	records map[string]car.Record
+	history map[string][]car.StatusChange
+	prices  map[string][]car.PriceChange
+	trash   map[string]TrashedRecord
+	indexes *indexes
+	vins    *vinRegistry
+	rules   *car.Rules
+	vocab   *vocab.Registry
+	clock   clock.Clock
+	mu      *sync.RWMutex
+}
+
+type Option func(*manager)
+
+// WithClock timestamps status and price changes with clk instead of the
+// wall clock.
+func WithClock(clk clock.Clock) Option {
+	return func(m *manager) {
+		m.clock = clk
+	}
+}
+
+// WithRules validates every record against rules on top of car.Record.Validate.
+func WithRules(rules *car.Rules) Option {
+	return func(m *manager) {
+		m.rules = rules
+	}
+}
+
+// WithVocabulary normalizes the make, model and category of every record
+// and rejects terms unknown to the registry.
+func WithVocabulary(registry *vocab.Registry) Option {
+	return func(m *manager) {
+		m.vocab = registry
+	}
+}
+
+func NewManager(opts ...Option) Manager {
+	m := &manager{
+		records: make(map[string]car.Record),
+		history: make(map[string][]car.StatusChange),
+		prices:  make(map[string][]car.PriceChange),
+		trash:   make(map[string]TrashedRecord),
+		indexes: newIndexes(),
+		vins:    newVINRegistry(),
+		clock:   clock.System,
+		mu:      &sync.RWMutex{},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+/*
+Add and Update validate the record before taking the lock, as validation
+only depends on the record, then check it against the store and save it
+under a single write lock so no other write can slip in between.
+*/
+func (s *manager) Add(ctx context.Context, record car.Record) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	record, err := s.validate(record)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(record)
+}
+
+func (s *manager) Get(ctx context.Context, recordID string) (car.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return car.Record{}, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, exists := s.records[recordID]
+	if !exists {
+		return car.Record{}, ErrorRecordNotFound{recordID}
+	}
+	return record, nil
+}
+
+func (s *manager) GetByVIN(ctx context.Context, vin string) (car.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return car.Record{}, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	id, exists := s.vins.lookup(vin)
+	if !exists {
+		return car.Record{}, ErrorVINNotFound{vin}
+	}
+	return s.records[id], nil
+}
+
+func (s *manager) List(ctx context.Context) ([]car.Record, error) {
+	return s.Query(ctx, Query{})
+}
+
+// Update overwrites the whole record.
+func (s *manager) Update(ctx context.Context, record car.Record) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	record, err := s.validate(record)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.replace(record)
+}
+
+/*
+Patch replaces a car with what fn makes of it and returns the car as
+stored. The car is read and replaced under one write lock, so no other
+write comes in between and changes made meanwhile are not lost. The car
+keeps its ID whatever fn returns, and nothing changes when fn fails. fn
+must not call the manager.
+*/
+func (s *manager) Patch(ctx context.Context, carID string, fn func(car.Record) (car.Record, error)) (car.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return car.Record{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, exists := s.records[carID]
+	if !exists {
+		return car.Record{}, ErrorRecordNotFound{carID}
+	}
+	record, err := fn(current)
+	if err != nil {
+		return car.Record{}, err
+	}
+	record.ID = carID
+	if record, err = s.validate(record); err != nil {
+		return car.Record{}, err
+	}
+	if err := s.replace(record); err != nil {
+		return car.Record{}, err
+	}
+	return s.records[carID], nil
+}
+
+// validate checks record and returns it normalized, as it should be stored.
+func (s *manager) validate(record car.Record) (car.Record, error) {
+	record, err := s.vocab.Normalize(record)
+	if err != nil {
+		return car.Record{}, err
+	}
+	if err := record.Validate(); err != nil {
+		return car.Record{}, err
+	}
+	if err := s.rules.Validate(record); err != nil {
+		return car.Record{}, err
+	}
+	return record, nil
+}
+
+// claimVIN reserves the VIN of record for it, and reports whether another
+// record holds it. It must be the last check before storing the record.
+// s.mu must be held for writing.
+func (s *manager) claimVIN(record car.Record) error {
+	if !s.vins.claim(record.VIN, record.ID) {
+		return ErrorVINAlreadyExists{record.VIN}
+	}
+	return nil
+}
+
+// insert stores a new record. s.mu must be held for writing.
+func (s *manager) insert(record car.Record) error {
+	// A trashed car keeps its ID until purged so it can be restored.
+	_, exists := s.records[record.ID]
+	_, trashed := s.trash[record.ID]
+	if exists || trashed {
+		return ErrorAlreadyExists{record.ID}
+	}
+	// Other statuses are only reached through transitions, which record
+	// them in the history.
+	switch record.Status {
+	case "":
+		record.Status = car.StatusAvailable
+	case car.StatusAvailable:
+	default:
+		return ErrorInitialStatus{record.ID, record.Status}
+	}
+	if err := s.claimVIN(record); err != nil {
+		return err
+	}
+	s.storeRecord(record)
+	return nil
+}
+
+// replace overwrites a stored record. s.mu must be held for writing.
+func (s *manager) replace(record car.Record) error {
+	current, exists := s.records[record.ID]
+	if !exists {
+		return ErrorRecordNotFound{record.ID}
+	}
+	// Status may only change along the allowed transitions.
+	if record.Status == "" {
+		record.Status = current.Status
+	}
+	if record.Status != current.Status && !current.Status.CanTransitionTo(record.Status) {
+		return ErrorIllegalTransition{record.ID, current.Status, record.Status}
+	}
+	if err := s.claimVIN(record); err != nil {
+		return err
+	}
+	s.storeRecord(record)
+	return nil
+}
+
+// storeRecord saves record, its indexes and its status and price history.
+// s.mu must be held for writing.
+func (s *manager) storeRecord(record car.Record) {
+	now := s.clock.Now()
+	old, exists := s.records[record.ID]
+	if exists {
+		s.indexes.remove(old)
+		if old.VIN != record.VIN {
+			s.vins.release(old.VIN, old.ID)
+		}
+	}
+	if !exists || old.Status != record.Status {
+		s.history[record.ID] = append(s.history[record.ID], car.StatusChange{
+			From: old.Status,
+			To:   record.Status,
+			At:   now,
+		})
+	}
+	if !exists || old.Price != record.Price {
+		s.prices[record.ID] = append(s.prices[record.ID], car.PriceChange{
+			Price: record.Price,
+			At:    now,
+		})
+	}
+	if exists && old.Price != record.Price {
+		s.indexes.priceChanged(record.ID, old.Price, record.Price, now)
+	}
+	s.records[record.ID] = record
+	s.indexes.add(record)
+}
@@ -0,0 +1,500 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+func TestManager_Add(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+
+	validRecord := car.Record{
+		ID:       "123",
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     time.Now().Year(),
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+		Status:   car.StatusAvailable,
+	}
+
+	invalidRecord := validRecord
+	invalidRecord.ID = ""
+
+	soldRecord := validRecord
+	soldRecord.ID = "456"
+	soldRecord.Status = car.StatusSold
+
+	tests := []struct {
+		name    string
+		record  car.Record
+		wantErr error
+	}{
+		// Valid
+		{
+			name:    "Add valid record",
+			record:  validRecord,
+			wantErr: nil,
+		},
+		// Errors
+		{
+			name:    "Duplicate record ID",
+			record:  validRecord,
+			wantErr: ErrorAlreadyExists{"123"},
+		},
+		{
+			name:    "Invalid record",
+			record:  invalidRecord,
+			wantErr: car.ErrorFieldMissing{Field: "ID"},
+		},
+		{
+			name:    "Record not available",
+			record:  soldRecord,
+			wantErr: ErrorInitialStatus{"456", car.StatusSold},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testManager.Add(ctx, tt.record)
+			if err != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("unexpected error, wanted success, got: %v", err)
+				}
+				if err.Error() != tt.wantErr.Error() {
+					t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+				}
+			} else if tt.wantErr != nil {
+				t.Fatalf("unexpected success, expected error: %s", tt.wantErr.Error())
+			}
+			if m, _ := testManager.(*manager); !reflect.DeepEqual(m.records[validRecord.ID], validRecord) {
+				t.Fatalf("unexpected records in manager, expected to find one record: %v, but got: %v", validRecord, m.records)
+			}
+		})
+	}
+}
+
+func TestManager_Get(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+
+	record := car.Record{
+		ID:       "123",
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     time.Now().Year(),
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+		Status:   car.StatusAvailable,
+	}
+
+	_ = testManager.Add(ctx, record)
+
+	tests := []struct {
+		name       string
+		id         string
+		wantRecord car.Record
+		wantErr    error
+	}{
+		// Valid
+		{
+			name:       "Get valid record",
+			id:         "123",
+			wantRecord: record,
+			wantErr:    nil,
+		},
+		// Error
+		{
+			name:    "Record not found",
+			id:      "456",
+			wantErr: ErrorRecordNotFound{"456"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRecord, gotErr := testManager.Get(ctx, tt.id)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("unexpected error, wanted success, got: %v", gotErr)
+				}
+				if gotErr.Error() != tt.wantErr.Error() {
+					t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, gotErr)
+				}
+			} else if tt.wantErr != nil {
+				t.Fatalf("unexpected success, expected error: %s", tt.wantErr.Error())
+			}
+			if gotErr == nil && !reflect.DeepEqual(gotRecord, record) {
+				t.Fatalf("unexpected record obtained, expected: %v, got: %v", record, gotRecord)
+			}
+		})
+	}
+}
+
+func TestManager_List(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+
+	// Setup some records
+	record1 := car.Record{
+		ID:       "123",
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     time.Now().Year(),
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+		Status:   car.StatusAvailable,
+	}
+
+	record2 := car.Record{
+		ID:       "124",
+		Make:     "Honda",
+		Model:    "Civic",
+		Category: "Coupe",
+		Package:  "Premium",
+		Color:    "Red",
+		Year:     time.Now().Year(),
+		Mileage:  500,
+		Price:    car.NewMoney(12000, "USD"),
+		Status:   car.StatusAvailable,
+	}
+
+	_ = testManager.Add(ctx, record1)
+	_ = testManager.Add(ctx, record2)
+
+	tests := []struct {
+		name     string
+		wantList []car.Record
+	}{
+		{
+			name:     "List records",
+			wantList: []car.Record{record1, record2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotList := mustList(t, testManager)
+			for _, record := range tt.wantList {
+				if !slices.Contains[[]car.Record, car.Record](gotList, record) {
+					t.Fatalf("unexpected list, wanted: %v, got: %v", tt.wantList, gotList)
+				}
+			}
+		})
+	}
+}
+
+func TestManager_Update(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+
+	record := car.Record{
+		ID:       "123",
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     time.Now().Year(),
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+	}
+
+	_ = testManager.Add(ctx, record)
+
+	updatedRecord := record
+	updatedRecord.Make = "Honda"
+
+	nonexistentRecord := record
+	nonexistentRecord.ID = "456"
+
+	tests := []struct {
+		name    string
+		record  car.Record
+		wantErr error
+	}{
+		{
+			name:    "Update valid record",
+			record:  updatedRecord,
+			wantErr: nil,
+		},
+		{
+			name:    "Record not found",
+			record:  nonexistentRecord,
+			wantErr: ErrorRecordNotFound{"456"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testManager.Update(ctx, tt.record)
+			if err != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("unexpected error, wanted success, got: %v", err)
+				}
+				if err.Error() != tt.wantErr.Error() {
+					t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+				}
+			} else if tt.wantErr != nil {
+				t.Fatalf("unexpected success, expected error: %s", tt.wantErr.Error())
+			}
+			if m, _ := testManager.(*manager); err == nil && reflect.DeepEqual(m.records[record.ID], record) {
+				t.Fatalf("unexpected record, expected: %v, but got: %v", record, m.records[record.ID])
+			}
+		})
+	}
+}
+
+func TestManager_Patch(t *testing.T) {
+	ctx := context.Background()
+
+	record := car.Record{
+		ID:       "123",
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     2020,
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+	}
+	failure := errors.New("failure")
+
+	tests := []struct {
+		name    string
+		carID   string
+		fn      func(car.Record) (car.Record, error)
+		want    car.Record
+		wantErr error
+	}{
+		{"patch", "123", func(r car.Record) (car.Record, error) {
+			r.Color = "Red"
+			r.ID = "456"
+			return r, nil
+		}, car.Record{Color: "Red"}, nil},
+		{"fn fails", "123", func(r car.Record) (car.Record, error) {
+			return car.Record{}, failure
+		}, car.Record{Color: "Red"}, failure},
+		{"invalid", "123", func(r car.Record) (car.Record, error) {
+			r.Make = ""
+			return r, nil
+		}, car.Record{Color: "Red"}, car.ErrorFieldMissing{Field: "Make"}},
+		{"not found", "456", func(r car.Record) (car.Record, error) {
+			return r, nil
+		}, car.Record{Color: "Red"}, ErrorRecordNotFound{"456"}},
+	}
+
+	for name, testManager := range map[string]Manager{
+		"manager": NewManager(),
+		"sharded": NewShardedManager(4),
+	} {
+		_ = testManager.Add(ctx, record)
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				_, err := testManager.Patch(ctx, tt.carID, tt.fn)
+				if !reflect.DeepEqual(err, tt.wantErr) {
+					t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+				}
+				stored, _ := testManager.Get(ctx, "123")
+				if stored.Color != tt.want.Color {
+					t.Fatalf("unexpected color, wanted: %s, got: %s", tt.want.Color, stored.Color)
+				}
+				if _, err := testManager.Get(ctx, "456"); err == nil {
+					t.Fatal("expected the patched car to keep its ID")
+				}
+			})
+		}
+	}
+}
+
+func TestManager_VIN(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+
+	record := car.Record{
+		ID:       "123",
+		Make:     "Honda",
+		Model:    "Accord",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     2003,
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+		Status:   car.StatusAvailable,
+		VIN:      "1HGCM82633A004352",
+	}
+
+	if err := testManager.Add(ctx, record); err != nil {
+		t.Fatalf("unexpected error adding record: %v", err)
+	}
+
+	duplicateVIN := record
+	duplicateVIN.ID = "456"
+	err := testManager.Add(ctx, duplicateVIN)
+	wantErr := ErrorVINAlreadyExists{record.VIN}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+
+	gotRecord, err := testManager.GetByVIN(ctx, record.VIN)
+	if err != nil || !reflect.DeepEqual(gotRecord, record) {
+		t.Fatalf("unexpected lookup by VIN, wanted: %v, got: %v, error: %v", record, gotRecord, err)
+	}
+
+	// Updating a record keeps its own VIN and releases it when removed.
+	updatedRecord := record
+	updatedRecord.Color = "Red"
+	if err := testManager.Update(ctx, updatedRecord); err != nil {
+		t.Fatalf("unexpected error updating record with its own VIN: %v", err)
+	}
+	updatedRecord.VIN = ""
+	if err := testManager.Update(ctx, updatedRecord); err != nil {
+		t.Fatalf("unexpected error removing VIN: %v", err)
+	}
+
+	_, err = testManager.GetByVIN(ctx, record.VIN)
+	wantErr2 := ErrorVINNotFound{record.VIN}
+	if err == nil || err.Error() != wantErr2.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr2, err)
+	}
+	if err := testManager.Add(ctx, duplicateVIN); err != nil {
+		t.Fatalf("expected released VIN to be reusable, got: %v", err)
+	}
+}
+
+func TestManager_Rules(t *testing.T) {
+	ctx := context.Background()
+
+	rules, err := car.ParseRules([]byte(`{"rules": [{"field": "year", "min": 2005}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error parsing rules: %v", err)
+	}
+	testManager := NewManager(WithRules(rules))
+
+	record := car.Record{
+		ID:       "123",
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     2004,
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+	}
+
+	err = testManager.Add(ctx, record)
+	wantErr := car.ErrorFieldInvalid{Field: "Year", Value: 2004}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+
+	record.Year = 2005
+	if err := testManager.Add(ctx, record); err != nil {
+		t.Fatalf("unexpected error, wanted success, got: %v", err)
+	}
+
+	record.Year = 2004
+	err = testManager.Update(ctx, record)
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error on update, wanted: %v, got: %v", wantErr, err)
+	}
+}
+
+// The helpers below read a manager that is not expected to fail, as these
+// reads only fail once their context is done.
+
+func mustList(t testing.TB, m Manager) []car.Record {
+	t.Helper()
+	records, err := m.List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error listing records: %v", err)
+	}
+	return records
+}
+
+func mustQuery(t testing.TB, m Manager, q Query) []car.Record {
+	t.Helper()
+	records, err := m.Query(context.Background(), q)
+	if err != nil {
+		t.Fatalf("unexpected error querying records: %v", err)
+	}
+	return records
+}
+
+func mustTrash(t testing.TB, m Manager) []TrashedRecord {
+	t.Helper()
+	trash, err := m.Trash(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error listing the trash: %v", err)
+	}
+	return trash
+}
+
+func mustDump(t testing.TB, m Manager) State {
+	t.Helper()
+	state, err := m.Dump(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error dumping the manager: %v", err)
+	}
+	return state
+}
+
+func BenchmarkManager_Update(b *testing.B) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+	for i := 0; i < 200000; i++ {
+		_ = testManager.Add(ctx, car.Record{
+			ID:       fmt.Sprintf("%06d", i),
+			Make:     "Toyota",
+			Model:    "Camry",
+			Category: "Sedan",
+			Package:  "Standard",
+			Color:    "Blue",
+			Year:     1990 + i%30,
+			Mileage:  i,
+			Price:    car.NewMoney(1000+i, "USD"),
+		})
+	}
+	record, _ := testManager.Get(ctx, "100000")
+
+	b.Run("update", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			record.Mileage = i
+			_ = testManager.Update(ctx, record)
+		}
+	})
+	b.Run("tx", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			record.Mileage = i
+			_ = testManager.Tx(ctx, func(tx Tx) error {
+				return tx.Update(record)
+			})
+		}
+	})
+}
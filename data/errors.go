@@ -0,0 +1,76 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+type ErrorAlreadyExists struct {
+	ID string
+}
+
+func (e ErrorAlreadyExists) Error() string {
+	return fmt.Sprintf("entry with ID '%s' alrady exists", e.ID)
+}
+
+type ErrorRecordNotFound struct {
+	ID string
+}
+
+func (e ErrorRecordNotFound) Error() string {
+	return fmt.Sprintf("no record in store with ID: '%s'", e.ID)
+}
+
+type ErrorVINAlreadyExists struct {
+	VIN string
+}
+
+func (e ErrorVINAlreadyExists) Error() string {
+	return fmt.Sprintf("entry with VIN '%s' already exists", e.VIN)
+}
+
+type ErrorVINNotFound struct {
+	VIN string
+}
+
+func (e ErrorVINNotFound) Error() string {
+	return fmt.Sprintf("no record in store with VIN: '%s'", e.VIN)
+}
+
+type ErrorNotInTrash struct {
+	ID string
+}
+
+func (e ErrorNotInTrash) Error() string {
+	return fmt.Sprintf("no record in trash with ID: '%s'", e.ID)
+}
+
+type ErrorIllegalTransition struct {
+	ID   string
+	From car.Status
+	To   car.Status
+}
+
+func (e ErrorIllegalTransition) Error() string {
+	return fmt.Sprintf("car '%s' cannot go from '%s' to '%s'", e.ID, e.From, e.To)
+}
+
+// ErrorCurrencyMissing is returned for queries bounding prices without a
+// currency, as amounts in different currencies do not compare.
+type ErrorCurrencyMissing struct{}
+
+func (e ErrorCurrencyMissing) Error() string {
+	return "price bounds need a currency"
+}
+
+// ErrorInitialStatus is returned for a car added with a status other than
+// available, which it may only reach through transitions.
+type ErrorInitialStatus struct {
+	ID     string
+	Status car.Status
+}
+
+func (e ErrorInitialStatus) Error() string {
+	return fmt.Sprintf("car '%s' must be added as '%s', not '%s'", e.ID, car.StatusAvailable, e.Status)
+}
@@ -0,0 +1,376 @@
+package data
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+// valueIndex maps a field value to the set of record IDs holding it.
+type valueIndex map[string]map[string]struct{}
+
+func (idx valueIndex) add(value, id string) {
+	ids, ok := idx[value]
+	if !ok {
+		ids = make(map[string]struct{})
+		idx[value] = ids
+	}
+	ids[id] = struct{}{}
+}
+
+func (idx valueIndex) remove(value, id string) {
+	ids, ok := idx[value]
+	if !ok {
+		return
+	}
+	delete(ids, id)
+	if len(ids) == 0 {
+		delete(idx, value)
+	}
+}
+
+type indexEntry[T cmp.Ordered] struct {
+	value T
+	id    string
+}
+
+func compareEntries[T cmp.Ordered](a, b indexEntry[T]) int {
+	if c := cmp.Compare(a.value, b.value); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.id, b.id)
+}
+
+// maxBlock is the most entries a block of an orderedIndex holds before it
+// is split in two.
+const maxBlock = 512
+
+/*
+orderedIndex keeps record IDs sorted by a field value, ties broken by ID.
+The entries are split into sorted blocks of at most maxBlock, so a change
+only moves the entries of one block and finding a position walks the
+block lengths rather than the entries. Positions count entries from the
+start of the index.
+*/
+type orderedIndex[T cmp.Ordered] struct {
+	blocks [][]indexEntry[T]
+	size   int
+}
+
+// find returns the block an entry belongs in, the first whose last entry
+// is not before it, and the position of the entry in the block.
+func (idx *orderedIndex[T]) find(entry indexEntry[T]) (int, int, bool) {
+	b, _ := slices.BinarySearchFunc(idx.blocks, entry, func(block []indexEntry[T], entry indexEntry[T]) int {
+		return compareEntries(block[len(block)-1], entry)
+	})
+	if b == len(idx.blocks) {
+		b--
+	}
+	i, found := slices.BinarySearchFunc(idx.blocks[b], entry, compareEntries[T])
+	return b, i, found
+}
+
+func (idx *orderedIndex[T]) insert(value T, id string) {
+	entry := indexEntry[T]{value, id}
+	idx.size++
+	if len(idx.blocks) == 0 {
+		idx.blocks = [][]indexEntry[T]{{entry}}
+		return
+	}
+	b, i, _ := idx.find(entry)
+	block := slices.Insert(idx.blocks[b], i, entry)
+	if len(block) <= maxBlock {
+		idx.blocks[b] = block
+		return
+	}
+	half := len(block) / 2
+	idx.blocks[b] = block[:half:half]
+	idx.blocks = slices.Insert(idx.blocks, b+1, slices.Clone(block[half:]))
+}
+
+func (idx *orderedIndex[T]) remove(value T, id string) {
+	if len(idx.blocks) == 0 {
+		return
+	}
+	b, i, found := idx.find(indexEntry[T]{value, id})
+	if !found {
+		return
+	}
+	idx.size--
+	idx.blocks[b] = slices.Delete(idx.blocks[b], i, i+1)
+	if len(idx.blocks[b]) == 0 {
+		idx.blocks = slices.Delete(idx.blocks, b, b+1)
+	}
+}
+
+// position returns how many entries come before the bound, before
+// reporting whether an entry does, which must hold for a prefix of the
+// index.
+func (idx *orderedIndex[T]) position(before func(indexEntry[T]) bool) int {
+	n := 0
+	for _, block := range idx.blocks {
+		if !before(block[len(block)-1]) {
+			i, _ := slices.BinarySearchFunc(block, true, func(e indexEntry[T], _ bool) int {
+				if before(e) {
+					return -1
+				}
+				return 1
+			})
+			return n + i
+		}
+		n += len(block)
+	}
+	return n
+}
+
+// window returns the half-open range of positions of the entries whose
+// value lies within the given bounds, a nil bound being open.
+func (idx *orderedIndex[T]) window(min, max *T) (int, int) {
+	lo, hi := 0, idx.size
+	if min != nil {
+		lo = idx.position(func(e indexEntry[T]) bool { return e.value < *min })
+	}
+	if max != nil {
+		hi = idx.position(func(e indexEntry[T]) bool { return e.value <= *max })
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// scan calls fn with the IDs at the positions from lo to hi, excluded, in
+// reverse order when desc, until fn returns false.
+func (idx *orderedIndex[T]) scan(lo, hi int, desc bool, fn func(id string) bool) {
+	if lo >= hi {
+		return
+	}
+	if desc {
+		// start is the position of the first entry of block b.
+		b, start := 0, 0
+		for start+len(idx.blocks[b]) < hi {
+			start += len(idx.blocks[b])
+			b++
+		}
+		for ; b >= 0; b-- {
+			block := idx.blocks[b]
+			for i := min(hi, start+len(block)) - 1; i >= start; i-- {
+				if i < lo || !fn(block[i-start].id) {
+					return
+				}
+			}
+			if b > 0 {
+				start -= len(idx.blocks[b-1])
+			}
+		}
+		return
+	}
+
+	start := 0
+	for _, block := range idx.blocks {
+		if start+len(block) <= lo {
+			start += len(block)
+			continue
+		}
+		for i := max(lo, start); i < start+len(block); i++ {
+			if i >= hi || !fn(block[i-start].id) {
+				return
+			}
+		}
+		start += len(block)
+	}
+}
+
+// ids returns the IDs at the positions from lo to hi, excluded.
+func (idx *orderedIndex[T]) ids(lo, hi int) []string {
+	ids := make([]string, 0, max(hi-lo, 0))
+	idx.scan(lo, hi, false, func(id string) bool {
+		ids = append(ids, id)
+		return true
+	})
+	return ids
+}
+
+// indexes holds every secondary index of the manager. It is not safe for
+// concurrent use, callers must hold the manager lock.
+type indexes struct {
+	byMake     valueIndex
+	byModel    valueIndex
+	byCategory valueIndex
+	byColor    valueIndex
+	byCurrency valueIndex
+	byStatus   valueIndex
+
+	byID    orderedIndex[string]
+	byYear  orderedIndex[int]
+	byPrice orderedIndex[int]
+	// byMileage orders odometers in meters so miles and kilometers mix.
+	byMileage orderedIndex[int]
+
+	// byPriceDrop orders records by the Unix time in nanoseconds of their
+	// latest price drop, kept in lastDrop. Records never discounted, or
+	// whose price went back up since, are left out.
+	byPriceDrop orderedIndex[int64]
+	lastDrop    map[string]priceDrop
+}
+
+/*
+priceDrop is the latest drop of the price of a record while it stands: at
+is its Unix time in nanoseconds and from the highest amount the price had
+before it, in the currency of the current price. Consecutive drops keep
+the amount before the first one, and raising the price back to it ends
+the drop.
+*/
+type priceDrop struct {
+	at   int64
+	from int
+}
+
+// nextDrop returns the drop standing once the price of a record changed
+// from before to after at the given time. Prices in different currencies
+// do not compare, so a new currency ends the drop and is not one.
+func nextDrop(drop priceDrop, dropped bool, before, after car.Money, at time.Time) (priceDrop, bool) {
+	switch {
+	case before.Currency != after.Currency:
+		return priceDrop{}, false
+	case after.Amount < before.Amount:
+		from := before.Amount
+		if dropped {
+			from = max(from, drop.from)
+		}
+		return priceDrop{at.UnixNano(), from}, true
+	case dropped && after.Amount >= drop.from:
+		return priceDrop{}, false
+	}
+	return drop, dropped
+}
+
+func newIndexes() *indexes {
+	return &indexes{
+		byMake:     make(valueIndex),
+		byModel:    make(valueIndex),
+		byCategory: make(valueIndex),
+		byColor:    make(valueIndex),
+		byCurrency: make(valueIndex),
+		byStatus:   make(valueIndex),
+		lastDrop:   make(map[string]priceDrop),
+	}
+}
+
+func (i *indexes) add(record car.Record) {
+	i.byMake.add(record.Make, record.ID)
+	i.byModel.add(record.Model, record.ID)
+	i.byCategory.add(record.Category, record.ID)
+	i.byColor.add(record.Color, record.ID)
+	i.byCurrency.add(record.Price.Currency, record.ID)
+	i.byStatus.add(string(record.Status), record.ID)
+
+	i.byID.insert(record.ID, record.ID)
+	i.byYear.insert(record.Year, record.ID)
+	i.byPrice.insert(record.Price.Amount, record.ID)
+	i.byMileage.insert(car.Meters(record.Mileage, record.OdometerUnit()), record.ID)
+}
+
+func (i *indexes) remove(record car.Record) {
+	i.byMake.remove(record.Make, record.ID)
+	i.byModel.remove(record.Model, record.ID)
+	i.byCategory.remove(record.Category, record.ID)
+	i.byColor.remove(record.Color, record.ID)
+	i.byCurrency.remove(record.Price.Currency, record.ID)
+	i.byStatus.remove(string(record.Status), record.ID)
+
+	i.byID.remove(record.ID, record.ID)
+	i.byYear.remove(record.Year, record.ID)
+	i.byPrice.remove(record.Price.Amount, record.ID)
+	i.byMileage.remove(car.Meters(record.Mileage, record.OdometerUnit()), record.ID)
+}
+
+// priceChanged updates the drop standing for a record whose price changed
+// from before to after at the given time.
+func (i *indexes) priceChanged(id string, before, after car.Money, at time.Time) {
+	last, wasDropped := i.lastDrop[id]
+	drop, dropped := nextDrop(last, wasDropped, before, after, at)
+	if drop != last || dropped != wasDropped {
+		i.setDrop(id, drop, dropped)
+	}
+}
+
+// setDrop replaces the drop standing for a record, there is none unless
+// dropped.
+func (i *indexes) setDrop(id string, drop priceDrop, dropped bool) {
+	if last, exists := i.lastDrop[id]; exists {
+		i.byPriceDrop.remove(last.at, id)
+		delete(i.lastDrop, id)
+	}
+	if dropped {
+		i.lastDrop[id] = drop
+		i.byPriceDrop.insert(drop.at, id)
+	}
+}
+
+// ordered returns the ordered index backing a sortable field.
+func (i *indexes) ordered(field SortField) *orderedIndex[int] {
+	switch field {
+	case SortByYear:
+		return &i.byYear
+	case SortByPrice:
+		return &i.byPrice
+	case SortByMileage:
+		return &i.byMileage
+	}
+	return nil
+}
+
+/*
+vinRegistry maps each VIN to the ID of the record holding it. It has a
+lock of its own so the shards of a sharded manager can share it, and is
+always locked after the manager lock.
+*/
+type vinRegistry struct {
+	ids map[string]string
+	mu  *sync.Mutex
+}
+
+func newVINRegistry() *vinRegistry {
+	return &vinRegistry{ids: make(map[string]string), mu: &sync.Mutex{}}
+}
+
+func (v *vinRegistry) lookup(vin string) (string, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	id, exists := v.ids[vin]
+	return id, exists
+}
+
+// claim gives vin to the record id unless another record holds it.
+func (v *vinRegistry) claim(vin, id string) bool {
+	if vin == "" {
+		return true
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if holder, exists := v.ids[vin]; exists && holder != id {
+		return false
+	}
+	v.ids[vin] = id
+	return true
+}
+
+// release frees vin if the record id holds it.
+func (v *vinRegistry) release(vin, id string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if holder, exists := v.ids[vin]; exists && holder == id {
+		delete(v.ids, vin)
+	}
+}
+
+// reset forgets every VIN.
+func (v *vinRegistry) reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.ids = make(map[string]string)
+}
@@ -0,0 +1,64 @@
+package data
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestOrderedIndex(t *testing.T) {
+	// Enough entries to split blocks, with duplicate values.
+	random := rand.New(rand.NewSource(1))
+	var idx orderedIndex[int]
+	var want []indexEntry[int]
+	for i := 0; i < 5*maxBlock; i++ {
+		entry := indexEntry[int]{random.Intn(1000), strconv.Itoa(i)}
+		idx.insert(entry.value, entry.id)
+		want = append(want, entry)
+	}
+	for i := 0; i < 2*maxBlock; i++ {
+		j := random.Intn(len(want))
+		idx.remove(want[j].value, want[j].id)
+		want = slices.Delete(want, j, j+1)
+	}
+	idx.remove(-1, "missing")
+	slices.SortFunc(want, compareEntries[int])
+
+	ids := func(entries []indexEntry[int]) []string {
+		ids := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.id)
+		}
+		return ids
+	}
+	if idx.size != len(want) || !reflect.DeepEqual(idx.ids(0, idx.size), ids(want)) {
+		t.Fatalf("unexpected entries, wanted %d, got %d", len(want), idx.size)
+	}
+
+	for _, bounds := range [][2]int{{0, 999}, {250, 750}, {500, 500}, {-5, 3}, {998, 2000}, {700, 300}} {
+		min, max := bounds[0], bounds[1]
+		lo, hi := idx.window(&min, &max)
+		var inside []indexEntry[int]
+		for _, entry := range want {
+			if entry.value >= min && entry.value <= max {
+				inside = append(inside, entry)
+			}
+		}
+		if hi-lo != len(inside) || !reflect.DeepEqual(idx.ids(lo, hi), ids(inside)) {
+			t.Fatalf("%v: unexpected window [%d, %d) of %d entries", bounds, lo, hi, len(inside))
+		}
+
+		var desc []string
+		idx.scan(lo, hi, true, func(id string) bool {
+			desc = append(desc, id)
+			return true
+		})
+		reversed := ids(inside)
+		slices.Reverse(reversed)
+		if len(desc) != len(reversed) || len(desc) > 0 && !reflect.DeepEqual(desc, reversed) {
+			t.Fatalf("%v: unexpected descending scan", bounds)
+		}
+	}
+}
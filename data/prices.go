@@ -0,0 +1,22 @@
+package data
+
+import (
+	"context"
+	"slices"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+// PriceHistory returns every price a car was listed at, oldest first.
+func (s *manager) PriceHistory(ctx context.Context, carID string) ([]car.PriceChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, exists := s.records[carID]; !exists {
+		return nil, ErrorRecordNotFound{carID}
+	}
+	return slices.Clone(s.prices[carID]), nil
+}
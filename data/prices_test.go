@@ -0,0 +1,59 @@
+package data
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/clock"
+)
+
+func TestManager_PriceHistory(t *testing.T) {
+	ctx := context.Background()
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	testManager := NewManager(WithClock(fake))
+
+	record := car.Record{
+		ID:       "123",
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     2020,
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+	}
+	_ = testManager.Add(ctx, record)
+	start := fake.Now()
+
+	// Changes other than the price are not recorded.
+	fake.Advance(time.Hour)
+	record.Color = "Red"
+	_ = testManager.Update(ctx, record)
+
+	fake.Advance(time.Hour)
+	record.Price = car.NewMoney(9000, "USD")
+	_ = testManager.Update(ctx, record)
+
+	got, err := testManager.PriceHistory(ctx, "123")
+	if err != nil {
+		t.Fatalf("unexpected error getting price history: %v", err)
+	}
+	want := []car.PriceChange{
+		{Price: car.NewMoney(10000, "USD"), At: start},
+		{Price: car.NewMoney(9000, "USD"), At: start.Add(2 * time.Hour)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected price history, wanted: %v, got: %v", want, got)
+	}
+
+	_, err = testManager.PriceHistory(ctx, "456")
+	wantErr := ErrorRecordNotFound{"456"}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+}
@@ -0,0 +1,341 @@
+package data
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+type SortField string
+
+const (
+	SortByID      SortField = "id"
+	SortByYear    SortField = "year"
+	SortByPrice   SortField = "price"
+	SortByMileage SortField = "mileage"
+)
+
+// Range bounds an ordered field inclusively, a nil bound is open.
+type Range struct {
+	Min *int
+	Max *int
+}
+
+func (r Range) set() bool {
+	return r.Min != nil || r.Max != nil
+}
+
+func (r Range) contains(value int) bool {
+	if r.Min != nil && value < *r.Min {
+		return false
+	}
+	if r.Max != nil && value > *r.Max {
+		return false
+	}
+	return true
+}
+
+/*
+Query filters and orders records. Empty string fields and open ranges
+do not filter. Price bounds are minor units of Currency, which they
+require. Ordering by price compares amounts in minor units whatever
+their currency, filter on Currency to compare like with like. Results
+are sorted by SortBy, then by ID, and a Limit of zero returns every
+match after Offset.
+*/
+type Query struct {
+	Make     string
+	Model    string
+	Category string
+	Color    string
+	VIN      string
+	Currency string
+	Status   car.Status
+
+	Year    Range
+	Price   Range
+	Mileage Range
+	// MileageUnit is the unit of the Mileage bounds, miles when empty.
+	MileageUnit car.DistanceUnit
+	// PriceDroppedSince keeps cars whose price was lowered at or after
+	// this time and has not gone back up to what it was before, it does
+	// not filter when zero.
+	PriceDroppedSince time.Time
+
+	SortBy SortField
+	Desc   bool
+	Offset int
+	Limit  int
+}
+
+// validate refuses price bounds without a currency to read them in.
+func (q Query) validate() error {
+	if q.Price.set() && q.Currency == "" {
+		return ErrorCurrencyMissing{}
+	}
+	return nil
+}
+
+func (q Query) matches(record car.Record) bool {
+	return (q.Make == "" || record.Make == q.Make) &&
+		(q.Model == "" || record.Model == q.Model) &&
+		(q.Category == "" || record.Category == q.Category) &&
+		(q.Color == "" || record.Color == q.Color) &&
+		(q.VIN == "" || record.VIN == q.VIN) &&
+		(q.Currency == "" || record.Price.Currency == q.Currency) &&
+		(q.Status == "" || record.Status == q.Status) &&
+		q.Year.contains(record.Year) &&
+		q.Price.contains(record.Price.Amount) &&
+		q.Mileage.contains(car.Meters(record.Mileage, record.OdometerUnit()))
+}
+
+// inMeters converts the bounds of r from unit to meters.
+func (r Range) inMeters(unit car.DistanceUnit) Range {
+	var meters Range
+	if r.Min != nil {
+		min := car.Meters(*r.Min, unit)
+		meters.Min = &min
+	}
+	if r.Max != nil {
+		max := car.Meters(*r.Max, unit)
+		meters.Max = &max
+	}
+	return meters
+}
+
+func (q Query) rangeFor(field SortField) Range {
+	switch field {
+	case SortByYear:
+		return q.Year
+	case SortByPrice:
+		return q.Price
+	case SortByMileage:
+		return q.Mileage
+	}
+	return Range{}
+}
+
+func sortValue(record car.Record, field SortField) int {
+	switch field {
+	case SortByYear:
+		return record.Year
+	case SortByPrice:
+		return record.Price.Amount
+	case SortByMileage:
+		return car.Meters(record.Mileage, record.OdometerUnit())
+	}
+	return 0
+}
+
+// matches reports whether record satisfies q, including the filters that
+// depend on the history of the record. Callers must hold the read lock.
+func (s *manager) matches(q Query, record car.Record) bool {
+	if !q.PriceDroppedSince.IsZero() {
+		last, dropped := s.indexes.lastDrop[record.ID]
+		if !dropped || last.at < q.PriceDroppedSince.UnixNano() {
+			return false
+		}
+	}
+	return q.matches(record)
+}
+
+func (s *manager) Query(ctx context.Context, q Query) ([]car.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.query(ctx, q)
+}
+
+/*
+query picks the most selective index available for q. When the index of
+the sort field yields the fewest candidates its entries are walked in
+order, stopping as soon as the page is full. Otherwise the smaller
+candidate set is filtered and sorted. It gives up with the error of ctx
+once ctx is done. Callers must hold the read lock.
+*/
+func (s *manager) query(ctx context.Context, q Query) ([]car.Record, error) {
+	if q.SortBy == "" {
+		q.SortBy = SortByID
+	}
+	if q.MileageUnit == "" {
+		q.MileageUnit = car.Miles
+	}
+	// Mileage is compared in meters from here on.
+	q.Mileage = q.Mileage.inMeters(q.MileageUnit)
+
+	var candidateSet map[string]struct{}
+	filtered := false
+
+	for _, eq := range []struct {
+		index valueIndex
+		value string
+	}{
+		{s.indexes.byMake, q.Make},
+		{s.indexes.byModel, q.Model},
+		{s.indexes.byCategory, q.Category},
+		{s.indexes.byColor, q.Color},
+		{s.indexes.byCurrency, q.Currency},
+		{s.indexes.byStatus, string(q.Status)},
+	} {
+		if eq.value == "" {
+			continue
+		}
+		ids := eq.index[eq.value]
+		if !filtered || len(ids) < len(candidateSet) {
+			candidateSet = ids
+			filtered = true
+		}
+	}
+	if filtered && len(candidateSet) == 0 {
+		return []car.Record{}, nil
+	}
+
+	best := -1
+	if filtered {
+		best = len(candidateSet)
+	}
+	// candidates lists the IDs of the most selective index, materialized
+	// only if the sort index is not walked.
+	candidates := func() []string {
+		ids := make([]string, 0, len(candidateSet))
+		for id := range candidateSet {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	for _, field := range []SortField{SortByYear, SortByPrice, SortByMileage} {
+		r := q.rangeFor(field)
+		if field == q.SortBy || !r.set() {
+			continue
+		}
+		index := s.indexes.ordered(field)
+		wlo, whi := index.window(r.Min, r.Max)
+		if best < 0 || whi-wlo < best {
+			best = whi - wlo
+			candidates = func() []string { return index.ids(wlo, whi) }
+		}
+	}
+	if !q.PriceDroppedSince.IsZero() {
+		since := q.PriceDroppedSince.UnixNano()
+		wlo, whi := s.indexes.byPriceDrop.window(&since, nil)
+		if best < 0 || whi-wlo < best {
+			best = whi - wlo
+			candidates = func() []string { return s.indexes.byPriceDrop.ids(wlo, whi) }
+		}
+	}
+
+	// Walking the sort index visits about (Offset+Limit)/selectivity
+	// entries before the page is full.
+	lo, hi, scan := s.sortWindow(q)
+	scanCost := hi - lo
+	if q.Limit > 0 && best > 0 {
+		if estimate := (q.Offset + q.Limit) * (hi - lo) / best; estimate < scanCost {
+			scanCost = estimate
+		}
+	}
+	if best >= 0 && best < scanCost {
+		return s.collect(ctx, q, candidates())
+	}
+
+	list := make([]car.Record, 0, pageSize(q, hi-lo))
+	skipped, n := 0, 0
+	var err error
+	scan(lo, hi, q.Desc, func(id string) bool {
+		if n%checkEvery == 0 {
+			if err = ctx.Err(); err != nil {
+				return false
+			}
+		}
+		n++
+		if filtered {
+			if _, ok := candidateSet[id]; !ok {
+				return true
+			}
+		}
+		record := s.records[id]
+		if !s.matches(q, record) {
+			return true
+		}
+		if skipped < q.Offset {
+			skipped++
+			return true
+		}
+		list = append(list, record)
+		return q.Limit <= 0 || len(list) < q.Limit
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// sortWindow returns the positions of the entries of the sort index within
+// the query range, and the scan of that index.
+func (s *manager) sortWindow(q Query) (int, int, func(lo, hi int, desc bool, fn func(id string) bool)) {
+	if q.SortBy == SortByID {
+		return 0, s.indexes.byID.size, s.indexes.byID.scan
+	}
+	index := s.indexes.ordered(q.SortBy)
+	r := q.rangeFor(q.SortBy)
+	lo, hi := index.window(r.Min, r.Max)
+	return lo, hi, index.scan
+}
+
+// collect filters and sorts an unordered candidate list.
+func (s *manager) collect(ctx context.Context, q Query, candidates []string) ([]car.Record, error) {
+	matches := make([]car.Record, 0, len(candidates))
+	for n, id := range candidates {
+		if n%checkEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if record := s.records[id]; s.matches(q, record) {
+			matches = append(matches, record)
+		}
+	}
+
+	slices.SortFunc(matches, compareRecords(q))
+	return paginate(matches, q.Offset, q.Limit), nil
+}
+
+// compareRecords orders records as the results of q.
+func compareRecords(q Query) func(a, b car.Record) int {
+	return func(a, b car.Record) int {
+		c := cmp.Compare(sortValue(a, q.SortBy), sortValue(b, q.SortBy))
+		if c == 0 {
+			c = cmp.Compare(a.ID, b.ID)
+		}
+		if q.Desc {
+			return -c
+		}
+		return c
+	}
+}
+
+func paginate(records []car.Record, offset, limit int) []car.Record {
+	if offset >= len(records) {
+		return []car.Record{}
+	}
+	records = records[offset:]
+	if limit > 0 && limit < len(records) {
+		records = records[:limit]
+	}
+	return records
+}
+
+func pageSize(q Query, max int) int {
+	if q.Limit > 0 && q.Limit < max {
+		return q.Limit
+	}
+	return max
+}
@@ -0,0 +1,350 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/clock"
+)
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func recordIDs(records []car.Record) []string {
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	return ids
+}
+
+func TestManager_Query(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+
+	base := car.Record{
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     time.Now().Year(),
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+	}
+
+	records := []struct {
+		id       string
+		make     string
+		color    string
+		year     int
+		price    int
+		currency string
+		mileage  int
+	}{
+		{"1", "Toyota", "Blue", 2010, 9000, "USD", 120000},
+		{"2", "Honda", "Red", 2015, 12000, "USD", 80000},
+		{"3", "Toyota", "Red", 2020, 21000, "USD", 30000},
+		{"4", "Ford", "Blue", 2018, 15000, "CAD", 60000},
+		{"5", "Toyota", "Blue", 2022, 26000, "USD", 5000},
+	}
+	for _, r := range records {
+		record := base
+		record.ID = r.id
+		record.Make = r.make
+		record.Color = r.color
+		record.Year = r.year
+		record.Price = car.Money{Amount: r.price, Currency: r.currency}
+		record.Mileage = r.mileage
+		if err := testManager.Add(ctx, record); err != nil {
+			t.Fatalf("unexpected error adding record: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		query   Query
+		wantIDs []string
+	}{
+		{
+			name:    "no filter sorts by ID",
+			query:   Query{},
+			wantIDs: []string{"1", "2", "3", "4", "5"},
+		},
+		{
+			name:    "filter by make",
+			query:   Query{Make: "Toyota"},
+			wantIDs: []string{"1", "3", "5"},
+		},
+		{
+			name:    "filter by make and color",
+			query:   Query{Make: "Toyota", Color: "Blue"},
+			wantIDs: []string{"1", "5"},
+		},
+		{
+			name:    "unknown make",
+			query:   Query{Make: "Tesla"},
+			wantIDs: []string{},
+		},
+		{
+			name:    "filter by currency",
+			query:   Query{Currency: "CAD"},
+			wantIDs: []string{"4"},
+		},
+		{
+			name:    "price range sorted by price",
+			query:   Query{Currency: "USD", Price: Range{Min: intPtr(10000), Max: intPtr(21000)}, SortBy: SortByPrice},
+			wantIDs: []string{"2", "3"},
+		},
+		{
+			name:    "price range in another currency",
+			query:   Query{Currency: "CAD", Price: Range{Max: intPtr(21000)}},
+			wantIDs: []string{"4"},
+		},
+		{
+			name:    "year range sorted by mileage descending",
+			query:   Query{Year: Range{Min: intPtr(2015)}, SortBy: SortByMileage, Desc: true},
+			wantIDs: []string{"2", "4", "3", "5"},
+		},
+		{
+			name:    "make filter sorted by year with pagination",
+			query:   Query{Make: "Toyota", SortBy: SortByYear, Offset: 1, Limit: 1},
+			wantIDs: []string{"3"},
+		},
+		{
+			name:    "mileage range without sort",
+			query:   Query{Mileage: Range{Max: intPtr(60000)}},
+			wantIDs: []string{"3", "4", "5"},
+		},
+		{
+			name:    "offset past results",
+			query:   Query{Offset: 10},
+			wantIDs: []string{},
+		},
+	}
+
+	// The drops of a loaded state are found again from the price history.
+	loaded := NewManager()
+	if err := loaded.Load(ctx, mustDump(t, testManager)); err != nil {
+		t.Fatalf("unexpected error loading state: %v", err)
+	}
+	for name, m := range map[string]Manager{"updated": testManager, "loaded": loaded} {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				gotIDs := recordIDs(mustQuery(t, m, tt.query))
+				if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+					t.Fatalf("unexpected query result, wanted: %v, got: %v", tt.wantIDs, gotIDs)
+				}
+			})
+		}
+	}
+}
+
+func TestManager_QueryPriceWithoutCurrency(t *testing.T) {
+	for name, m := range map[string]Manager{"manager": NewManager(), "sharded": NewShardedManager(4)} {
+		t.Run(name, func(t *testing.T) {
+			_, err := m.Query(context.Background(), Query{Price: Range{Min: intPtr(10000)}})
+			if err != (ErrorCurrencyMissing{}) {
+				t.Fatalf("unexpected error, wanted: %v, got: %v", ErrorCurrencyMissing{}, err)
+			}
+		})
+	}
+}
+
+func TestManager_QueryAfterUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+
+	record := car.Record{
+		ID:       "123",
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     time.Now().Year(),
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+		Status:   car.StatusAvailable,
+	}
+	_ = testManager.Add(ctx, record)
+
+	updatedRecord := record
+	updatedRecord.Color = "Red"
+	updatedRecord.Price = car.Money{Amount: 8000, Currency: "USD"}
+	_ = testManager.Update(ctx, updatedRecord)
+
+	if got := mustQuery(t, testManager, Query{Color: "Blue"}); len(got) != 0 {
+		t.Fatalf("expected stale color index entry to be removed, got: %v", got)
+	}
+	if got := mustQuery(t, testManager, Query{Currency: "USD", Price: Range{Min: intPtr(9000)}}); len(got) != 0 {
+		t.Fatalf("expected stale price index entry to be removed, got: %v", got)
+	}
+	got := mustQuery(t, testManager, Query{Color: "Red", Currency: "USD", Price: Range{Max: intPtr(8000)}})
+	if !reflect.DeepEqual(got, []car.Record{updatedRecord}) {
+		t.Fatalf("unexpected query result, wanted: %v, got: %v", []car.Record{updatedRecord}, got)
+	}
+}
+
+func TestManager_QueryMixedMileageUnits(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+
+	record := car.Record{
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     time.Now().Year(),
+		Price:    car.NewMoney(10000, "USD"),
+	}
+
+	// 10000 mi is about 16093 km.
+	for _, odometer := range []struct {
+		id      string
+		mileage int
+		unit    car.DistanceUnit
+	}{
+		{"miles", 10000, car.Miles},
+		{"legacy", 12000, ""},
+		{"short", 15000, car.Kilometers},
+		{"long", 17000, car.Kilometers},
+	} {
+		record.ID = odometer.id
+		record.Mileage = odometer.mileage
+		record.MileageUnit = odometer.unit
+		if err := testManager.Add(ctx, record); err != nil {
+			t.Fatalf("unexpected error adding record: %v", err)
+		}
+	}
+
+	gotIDs := recordIDs(mustQuery(t, testManager, Query{SortBy: SortByMileage}))
+	wantIDs := []string{"short", "miles", "long", "legacy"}
+	if !reflect.DeepEqual(gotIDs, wantIDs) {
+		t.Fatalf("unexpected order, wanted: %v, got: %v", wantIDs, gotIDs)
+	}
+
+	gotIDs = recordIDs(mustQuery(t, testManager, Query{Mileage: Range{Max: intPtr(16500)}, MileageUnit: car.Kilometers}))
+	wantIDs = []string{"miles", "short"}
+	if !reflect.DeepEqual(gotIDs, wantIDs) {
+		t.Fatalf("unexpected filter result in kilometers, wanted: %v, got: %v", wantIDs, gotIDs)
+	}
+
+	gotIDs = recordIDs(mustQuery(t, testManager, Query{Mileage: Range{Min: intPtr(10000)}}))
+	wantIDs = []string{"legacy", "long", "miles"}
+	if !reflect.DeepEqual(gotIDs, wantIDs) {
+		t.Fatalf("unexpected filter result in miles, wanted: %v, got: %v", wantIDs, gotIDs)
+	}
+}
+
+func TestManager_QueryPriceDropped(t *testing.T) {
+	ctx := context.Background()
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	testManager := NewManager(WithClock(fake))
+
+	record := car.Record{
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     2020,
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+	}
+	for _, id := range []string{"old-drop", "recent-drop", "raised", "currency", "unchanged", "drop-raise", "drop-back", "drops-raise"} {
+		record.ID = id
+		_ = testManager.Add(ctx, record)
+	}
+
+	reprice := func(id string, price car.Money) {
+		record.ID = id
+		record.Price = price
+		if err := testManager.Update(ctx, record); err != nil {
+			t.Fatalf("unexpected error updating %s: %v", id, err)
+		}
+	}
+
+	reprice("old-drop", car.NewMoney(9000, "USD"))
+	fake.Advance(7 * 24 * time.Hour)
+	since := fake.Now()
+	reprice("recent-drop", car.NewMoney(9500, "USD"))
+	reprice("raised", car.NewMoney(11000, "USD"))
+	// A lower amount in another currency is not comparable.
+	reprice("currency", car.NewMoney(9000, "EUR"))
+	// Raising the price back to what it was before a drop ends the drop,
+	// after one drop or several.
+	reprice("drop-raise", car.NewMoney(9000, "USD"))
+	reprice("drop-back", car.NewMoney(9000, "USD"))
+	reprice("drops-raise", car.NewMoney(9000, "USD"))
+	reprice("drops-raise", car.NewMoney(8000, "USD"))
+	fake.Advance(time.Hour)
+	reprice("old-drop", car.NewMoney(9500, "USD"))
+	reprice("drop-raise", car.NewMoney(10500, "USD"))
+	reprice("drop-back", car.NewMoney(10000, "USD"))
+	reprice("drops-raise", car.NewMoney(9500, "USD"))
+	reprice("drops-raise", car.NewMoney(10000, "USD"))
+
+	tests := []struct {
+		name    string
+		query   Query
+		wantIDs []string
+	}{
+		{"dropped since", Query{PriceDroppedSince: since}, []string{"recent-drop"}},
+		{"dropped at any time", Query{PriceDroppedSince: since.Add(-30 * 24 * time.Hour)}, []string{"old-drop", "recent-drop"}},
+		{"combined with sort", Query{PriceDroppedSince: since.Add(-30 * 24 * time.Hour), SortBy: SortByPrice, Desc: true}, []string{"recent-drop", "old-drop"}},
+		{"combined with filter", Query{PriceDroppedSince: since, Currency: "USD", Price: Range{Max: intPtr(9000)}}, []string{}},
+		{"no drop yet", Query{PriceDroppedSince: fake.Now().Add(time.Hour)}, []string{}},
+	}
+	// The drops of a loaded state are found again from the price history.
+	loaded := NewManager()
+	if err := loaded.Load(ctx, mustDump(t, testManager)); err != nil {
+		t.Fatalf("unexpected error loading state: %v", err)
+	}
+	for name, m := range map[string]Manager{"updated": testManager, "loaded": loaded} {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				gotIDs := recordIDs(mustQuery(t, m, tt.query))
+				if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+					t.Fatalf("unexpected query result, wanted: %v, got: %v", tt.wantIDs, gotIDs)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkManager_Query(b *testing.B) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+	makes := []string{"Toyota", "Honda", "Ford", "Mazda", "Kia"}
+	for i := 0; i < 100000; i++ {
+		_ = testManager.Add(ctx, car.Record{
+			ID:       fmt.Sprintf("%06d", i),
+			Make:     makes[i%len(makes)],
+			Model:    "Model",
+			Category: "Sedan",
+			Package:  "Standard",
+			Color:    "Blue",
+			Year:     1990 + i%30,
+			Mileage:  i,
+			Price:    car.NewMoney(1000+i, "USD"),
+		})
+	}
+
+	query := Query{Make: "Ford", SortBy: SortByPrice, Desc: true, Offset: 100, Limit: 20}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		testManager.Query(ctx, query)
+	}
+}
@@ -0,0 +1,343 @@
+package data
+
+import (
+	"cmp"
+	"context"
+	"hash/fnv"
+	"slices"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+/*
+shardedManager spreads records over several managers by a hash of their
+ID, each with its own lock, so writes to different shards do not wait
+for each other. The shards share a VIN registry to keep VINs unique.
+
+Operations on a single car lock its shard only. Query, List, Trash and
+Purge visit the shards one after the other and may see writes to some
+shards but not others, while Dump, Load and Tx lock every shard and see
+or change the whole store at once.
+*/
+type shardedManager struct {
+	shards []*manager
+	vins   *vinRegistry
+}
+
+// NewShardedManager returns a Manager split into the given number of
+// shards, each configured with opts.
+func NewShardedManager(shards int, opts ...Option) Manager {
+	if shards < 1 {
+		shards = 1
+	}
+
+	m := &shardedManager{
+		shards: make([]*manager, shards),
+		vins:   newVINRegistry(),
+	}
+	for i := range m.shards {
+		shard := NewManager(opts...).(*manager)
+		shard.vins = m.vins
+		m.shards[i] = shard
+	}
+	return m
+}
+
+func (m *shardedManager) shard(id string) *manager {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return m.shards[h.Sum32()%uint32(len(m.shards))]
+}
+
+// lock write locks every shard, always in the same order.
+func (m *shardedManager) lock() {
+	for _, shard := range m.shards {
+		shard.mu.Lock()
+	}
+}
+
+func (m *shardedManager) unlock() {
+	for i := len(m.shards) - 1; i >= 0; i-- {
+		m.shards[i].mu.Unlock()
+	}
+}
+
+func (m *shardedManager) rlock() {
+	for _, shard := range m.shards {
+		shard.mu.RLock()
+	}
+}
+
+func (m *shardedManager) runlock() {
+	for i := len(m.shards) - 1; i >= 0; i-- {
+		m.shards[i].mu.RUnlock()
+	}
+}
+
+func (m *shardedManager) Add(ctx context.Context, record car.Record) error {
+	return m.shard(record.ID).Add(ctx, record)
+}
+
+func (m *shardedManager) Get(ctx context.Context, carID string) (car.Record, error) {
+	return m.shard(carID).Get(ctx, carID)
+}
+
+func (m *shardedManager) GetByVIN(ctx context.Context, vin string) (car.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return car.Record{}, err
+	}
+	id, exists := m.vins.lookup(vin)
+	if !exists {
+		return car.Record{}, ErrorVINNotFound{vin}
+	}
+	record, err := m.shard(id).Get(ctx, id)
+	switch err.(type) {
+	case nil:
+		if record.VIN == vin {
+			return record, nil
+		}
+	case ErrorRecordNotFound:
+	default:
+		return car.Record{}, err
+	}
+	// The car changed since the lookup.
+	return car.Record{}, ErrorVINNotFound{vin}
+}
+
+func (m *shardedManager) List(ctx context.Context) ([]car.Record, error) {
+	return m.Query(ctx, Query{})
+}
+
+// Query asks every shard for the first Offset+Limit matches and merges
+// them.
+func (m *shardedManager) Query(ctx context.Context, q Query) ([]car.Record, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	if q.SortBy == "" {
+		q.SortBy = SortByID
+	}
+
+	page := q
+	page.Offset = 0
+	if q.Limit > 0 {
+		page.Limit = q.Offset + q.Limit
+	}
+
+	var matches []car.Record
+	for _, shard := range m.shards {
+		part, err := shard.Query(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, part...)
+	}
+	slices.SortFunc(matches, compareRecords(q))
+	return paginate(matches, q.Offset, q.Limit), nil
+}
+
+func (m *shardedManager) Update(ctx context.Context, record car.Record) error {
+	return m.shard(record.ID).Update(ctx, record)
+}
+
+// Patch locks the shard of the car only.
+func (m *shardedManager) Patch(ctx context.Context, carID string, fn func(car.Record) (car.Record, error)) (car.Record, error) {
+	return m.shard(carID).Patch(ctx, carID, fn)
+}
+
+func (m *shardedManager) Transition(ctx context.Context, carID string, to car.Status) (car.Record, error) {
+	return m.shard(carID).Transition(ctx, carID, to)
+}
+
+func (m *shardedManager) StatusHistory(ctx context.Context, carID string) ([]car.StatusChange, error) {
+	return m.shard(carID).StatusHistory(ctx, carID)
+}
+
+func (m *shardedManager) PriceHistory(ctx context.Context, carID string) ([]car.PriceChange, error) {
+	return m.shard(carID).PriceHistory(ctx, carID)
+}
+
+func (m *shardedManager) Delete(ctx context.Context, carID string) error {
+	return m.shard(carID).Delete(ctx, carID)
+}
+
+func (m *shardedManager) Trash(ctx context.Context) ([]TrashedRecord, error) {
+	var list []TrashedRecord
+	for _, shard := range m.shards {
+		part, err := shard.Trash(ctx)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, part...)
+	}
+	slices.SortFunc(list, compareTrashed)
+	return list, nil
+}
+
+func (m *shardedManager) Restore(ctx context.Context, carID string) (car.Record, error) {
+	return m.shard(carID).Restore(ctx, carID)
+}
+
+// Purge stops at the first shard visited after ctx is done, the cars
+// already purged stay purged and are returned with the error.
+func (m *shardedManager) Purge(ctx context.Context, retention time.Duration) ([]string, error) {
+	var purged []string
+	var err error
+	for _, shard := range m.shards {
+		var part []string
+		if part, err = shard.Purge(ctx, retention); err != nil {
+			break
+		}
+		purged = append(purged, part...)
+	}
+	slices.Sort(purged)
+	return purged, err
+}
+
+func (m *shardedManager) Dump(ctx context.Context) (State, error) {
+	if err := ctx.Err(); err != nil {
+		return State{}, err
+	}
+
+	m.rlock()
+	defer m.runlock()
+
+	state := State{
+		StatusHistory: make(map[string][]car.StatusChange),
+		Prices:        make(map[string][]car.PriceChange),
+	}
+	for _, shard := range m.shards {
+		part := shard.dump()
+		state.Records = append(state.Records, part.Records...)
+		state.Trash = append(state.Trash, part.Trash...)
+		for id, history := range part.StatusHistory {
+			state.StatusHistory[id] = history
+		}
+		for id, prices := range part.Prices {
+			state.Prices[id] = prices
+		}
+	}
+	slices.SortFunc(state.Records, func(a, b car.Record) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	slices.SortFunc(state.Trash, func(a, b TrashedRecord) int {
+		return cmp.Compare(a.Record.ID, b.Record.ID)
+	})
+	if state.Records == nil {
+		state.Records = []car.Record{}
+	}
+	if state.Trash == nil {
+		state.Trash = []TrashedRecord{}
+	}
+	return state, nil
+}
+
+func (m *shardedManager) Load(ctx context.Context, state State) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := checkState(state); err != nil {
+		return err
+	}
+
+	parts := make(map[*manager]*State, len(m.shards))
+	for _, shard := range m.shards {
+		parts[shard] = &State{
+			StatusHistory: make(map[string][]car.StatusChange),
+			Prices:        make(map[string][]car.PriceChange),
+		}
+	}
+	for _, record := range state.Records {
+		part := parts[m.shard(record.ID)]
+		part.Records = append(part.Records, record)
+	}
+	for _, trashed := range state.Trash {
+		part := parts[m.shard(trashed.Record.ID)]
+		part.Trash = append(part.Trash, trashed)
+	}
+	for id, history := range state.StatusHistory {
+		parts[m.shard(id)].StatusHistory[id] = history
+	}
+	for id, prices := range state.Prices {
+		parts[m.shard(id)].Prices[id] = prices
+	}
+
+	m.lock()
+	defer m.unlock()
+	m.vins.reset()
+	for _, shard := range m.shards {
+		shard.install(*parts[shard])
+	}
+	return nil
+}
+
+// Tx locks every shard for the whole transaction, so it is serializable
+// like the transactions of a single manager.
+func (m *shardedManager) Tx(ctx context.Context, fn func(Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.lock()
+	defer m.unlock()
+
+	t := &shardedTx{m: m, txs: make(map[*manager]*tx)}
+	defer func() {
+		if r := recover(); r != nil {
+			t.rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(t); err != nil {
+		t.rollback()
+		return err
+	}
+	return nil
+}
+
+// shardedTx runs a transaction of each shard touched.
+type shardedTx struct {
+	m     *shardedManager
+	txs   map[*manager]*tx
+	order []*tx
+}
+
+func (t *shardedTx) tx(id string) *tx {
+	shard := t.m.shard(id)
+	shardTx, exists := t.txs[shard]
+	if !exists {
+		shardTx = &tx{m: shard, saved: make(map[string]bool)}
+		t.txs[shard] = shardTx
+		t.order = append(t.order, shardTx)
+	}
+	return shardTx
+}
+
+func (t *shardedTx) Get(carID string) (car.Record, error) {
+	return t.tx(carID).Get(carID)
+}
+
+func (t *shardedTx) Add(record car.Record) error {
+	return t.tx(record.ID).Add(record)
+}
+
+func (t *shardedTx) Update(record car.Record) error {
+	return t.tx(record.ID).Update(record)
+}
+
+func (t *shardedTx) Delete(carID string) error {
+	return t.tx(carID).Delete(carID)
+}
+
+// rollback takes the changed cars out of every shard before putting any
+// back, as cars of different shards may have traded VINs.
+func (t *shardedTx) rollback() {
+	for _, shardTx := range t.order {
+		shardTx.unstore()
+	}
+	for i := len(t.order) - 1; i >= 0; i-- {
+		t.order[i].restore()
+	}
+}
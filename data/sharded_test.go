@@ -0,0 +1,234 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+func shardedTestRecords() []car.Record {
+	makes := []string{"Toyota", "Honda", "Ford"}
+	colors := []string{"Blue", "Red"}
+	records := make([]car.Record, 0, 200)
+	for i := 0; i < 200; i++ {
+		records = append(records, car.Record{
+			ID:       fmt.Sprintf("%03d", i),
+			Make:     makes[i%len(makes)],
+			Model:    "Model",
+			Category: "Sedan",
+			Package:  "Standard",
+			Color:    colors[i%len(colors)],
+			Year:     2000 + i%20,
+			Mileage:  (i * 7919) % 100000,
+			Price:    car.NewMoney(1000+(i*31)%5000, "USD"),
+		})
+	}
+	return records
+}
+
+func TestShardedManager_QueryMatchesManager(t *testing.T) {
+	ctx := context.Background()
+
+	single := NewManager()
+	sharded := NewShardedManager(8)
+	for _, record := range shardedTestRecords() {
+		if err := single.Add(ctx, record); err != nil {
+			t.Fatalf("unexpected error adding record: %v", err)
+		}
+		if err := sharded.Add(ctx, record); err != nil {
+			t.Fatalf("unexpected error adding record to shards: %v", err)
+		}
+	}
+
+	queries := []Query{
+		{},
+		{Make: "Toyota"},
+		{Make: "Honda", Color: "Red", SortBy: SortByPrice},
+		{Year: Range{Min: intPtr(2010)}, SortBy: SortByMileage, Desc: true, Offset: 5, Limit: 10},
+		{Currency: "USD", Price: Range{Max: intPtr(300000)}, SortBy: SortByYear, Limit: 7},
+		{Offset: 190, Limit: 20},
+		{Make: "Tesla"},
+	}
+	for i, q := range queries {
+		want := mustQuery(t, single, q)
+		got := mustQuery(t, sharded, q)
+		if !reflect.DeepEqual(recordIDs(got), recordIDs(want)) {
+			t.Fatalf("query %d: unexpected result, wanted: %v, got: %v", i, recordIDs(want), recordIDs(got))
+		}
+	}
+
+	if got, want := mustDump(t, sharded), mustDump(t, single); !reflect.DeepEqual(got.Records, want.Records) {
+		t.Fatalf("unexpected dump of the shards")
+	}
+}
+
+func TestShardedManager_VINAcrossShards(t *testing.T) {
+	ctx := context.Background()
+
+	sharded := NewShardedManager(8)
+
+	record := stressRecord("a")
+	record.VIN = "1HGCM82633A004352"
+	if err := sharded.Add(ctx, record); err != nil {
+		t.Fatalf("unexpected error adding record: %v", err)
+	}
+
+	// Try IDs until one lands on another shard.
+	owner := sharded.(*shardedManager).shard("a")
+	other := stressRecord("b")
+	for i := 0; sharded.(*shardedManager).shard(other.ID) == owner; i++ {
+		other.ID = fmt.Sprintf("b%d", i)
+	}
+	other.VIN = record.VIN
+	err := sharded.Add(ctx, other)
+	wantErr := ErrorVINAlreadyExists{record.VIN}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+
+	if got, err := sharded.GetByVIN(ctx, record.VIN); err != nil || got.ID != "a" {
+		t.Fatalf("unexpected lookup by VIN, got: %v, error: %v", got, err)
+	}
+
+	// The VIN follows the car through the trash.
+	_ = sharded.Delete(ctx, "a")
+	if err := sharded.Add(ctx, other); err != nil {
+		t.Fatalf("expected VIN of a trashed car to be free, got: %v", err)
+	}
+	if _, err := sharded.Restore(ctx, "a"); err == nil {
+		t.Fatalf("expected restore to fail while the VIN is taken")
+	}
+}
+
+func TestShardedManager_TxRollback(t *testing.T) {
+	ctx := context.Background()
+
+	sharded := NewShardedManager(8)
+	for _, record := range shardedTestRecords()[:20] {
+		_ = sharded.Add(ctx, record)
+	}
+	before := mustDump(t, sharded)
+
+	abort := errors.New("abort")
+	err := sharded.Tx(ctx, func(tx Tx) error {
+		for _, id := range []string{"001", "002", "003", "004"} {
+			record, err := tx.Get(id)
+			if err != nil {
+				return err
+			}
+			record.Category = "Coupe"
+			if err := tx.Update(record); err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete("005"); err != nil {
+			return err
+		}
+		return abort
+	})
+	if err != abort {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", abort, err)
+	}
+	if got := mustDump(t, sharded); !reflect.DeepEqual(got, before) {
+		t.Fatalf("expected rollback of every shard")
+	}
+
+	if err := sharded.Load(ctx, before); err != nil {
+		t.Fatalf("unexpected error loading state: %v", err)
+	}
+	if got := mustDump(t, sharded); !reflect.DeepEqual(got, before) {
+		t.Fatalf("unexpected state after load")
+	}
+}
+
+func TestShardedManager_ConcurrentUpdates(t *testing.T) {
+	ctx := context.Background()
+
+	sharded := NewShardedManager(8)
+	_ = sharded.Add(ctx, stressRecord("123"))
+
+	var added atomic.Int32
+	stress(func(i int) {
+		record := stressRecord(fmt.Sprintf("%d", i%8))
+		if err := sharded.Add(ctx, record); err == nil {
+			added.Add(1)
+		}
+		for n := 0; n < 20; n++ {
+			_ = sharded.Tx(ctx, func(tx Tx) error {
+				record, err := tx.Get("123")
+				if err != nil {
+					return err
+				}
+				record.Mileage++
+				return tx.Update(record)
+			})
+		}
+		_, _ = sharded.Query(ctx, Query{Make: "Honda", SortBy: SortByMileage})
+	})
+
+	if added.Load() != 8 {
+		t.Fatalf("expected each ID to be added once, got %d adds", added.Load())
+	}
+	record, _ := sharded.Get(ctx, "123")
+	if want := stressGoroutines * 20; record.Mileage != want {
+		t.Fatalf("lost updates, wanted mileage: %d, got: %d", want, record.Mileage)
+	}
+}
+
+/*
+benchmarkMixed runs a bulk price update style workload from parallel
+goroutines: writePercent of the operations change the price of a random
+car, the others read one.
+*/
+func benchmarkMixed(b *testing.B, newManager func() Manager, writePercent int) {
+	ctx := context.Background()
+
+	const size = 10000
+	testManager := newManager()
+	records := make([]car.Record, size)
+	for i := range records {
+		records[i] = stressRecord(fmt.Sprintf("%05d", i))
+		records[i].Price = car.NewMoney(1000+i, "USD")
+		_ = testManager.Add(ctx, records[i])
+	}
+
+	var seed atomic.Int64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		rng := rand.New(rand.NewSource(seed.Add(1)))
+		for pb.Next() {
+			record := records[rng.Intn(size)]
+			if rng.Intn(100) < writePercent {
+				record.Price = car.NewMoney(1000+rng.Intn(size), "USD")
+				_ = testManager.Update(ctx, record)
+			} else {
+				_, _ = testManager.Get(ctx, record.ID)
+			}
+		}
+	})
+}
+
+func BenchmarkManagers_Mixed(b *testing.B) {
+	managers := []struct {
+		name string
+		new  func() Manager
+	}{
+		{"single", func() Manager { return NewManager() }},
+		{"sharded-4", func() Manager { return NewShardedManager(4) }},
+		{"sharded-16", func() Manager { return NewShardedManager(16) }},
+		{"sharded-64", func() Manager { return NewShardedManager(64) }},
+	}
+	for _, writePercent := range []int{10, 50, 90} {
+		for _, m := range managers {
+			b.Run(fmt.Sprintf("%s/writes-%d%%", m.name, writePercent), func(b *testing.B) {
+				benchmarkMixed(b, m.new, writePercent)
+			})
+		}
+	}
+}
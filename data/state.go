@@ -0,0 +1,136 @@
+package data
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+// State is the whole content of a manager, as saved in snapshots.
+type State struct {
+	Records       []car.Record                  `json:"records"`
+	StatusHistory map[string][]car.StatusChange `json:"status_history"`
+	Prices        map[string][]car.PriceChange  `json:"prices"`
+	Trash         []TrashedRecord               `json:"trash"`
+}
+
+/*
+Dump returns a consistent copy of the manager content. The read lock is
+only held while copying, so writers wait for a copy of the store rather
+than for the copy to be encoded or written.
+*/
+func (s *manager) Dump(ctx context.Context) (State, error) {
+	if err := ctx.Err(); err != nil {
+		return State{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dump(), nil
+}
+
+// dump copies the content of the manager. s.mu must be held.
+func (s *manager) dump() State {
+	state := State{
+		Records:       make([]car.Record, 0, len(s.records)),
+		StatusHistory: make(map[string][]car.StatusChange, len(s.history)),
+		Prices:        make(map[string][]car.PriceChange, len(s.prices)),
+		Trash:         make([]TrashedRecord, 0, len(s.trash)),
+	}
+	s.indexes.byID.scan(0, s.indexes.byID.size, false, func(id string) bool {
+		state.Records = append(state.Records, s.records[id])
+		return true
+	})
+	for id, history := range s.history {
+		state.StatusHistory[id] = slices.Clone(history)
+	}
+	for id, prices := range s.prices {
+		state.Prices[id] = slices.Clone(prices)
+	}
+	for _, trashed := range s.trash {
+		state.Trash = append(state.Trash, trashed)
+	}
+	slices.SortFunc(state.Trash, func(a, b TrashedRecord) int {
+		return cmp.Compare(a.Record.ID, b.Record.ID)
+	})
+	return state
+}
+
+/*
+Load replaces the whole content of the manager with state. Records are
+not validated again, as rules or vocabulary may have changed since the
+state was dumped, but IDs and VINs must be unique.
+*/
+func (s *manager) Load(ctx context.Context, state State) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := checkState(state); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vins.reset()
+	s.install(state)
+	return nil
+}
+
+// checkState reports the first ID or VIN held by two cars of state.
+func checkState(state State) error {
+	ids := make(map[string]struct{}, len(state.Records)+len(state.Trash))
+	vins := make(map[string]struct{}, len(state.Records))
+	for _, record := range state.Records {
+		if _, exists := ids[record.ID]; exists {
+			return ErrorAlreadyExists{record.ID}
+		}
+		ids[record.ID] = struct{}{}
+		if _, taken := vins[record.VIN]; record.VIN != "" && taken {
+			return ErrorVINAlreadyExists{record.VIN}
+		}
+		vins[record.VIN] = struct{}{}
+	}
+	for _, trashed := range state.Trash {
+		if _, exists := ids[trashed.Record.ID]; exists {
+			return ErrorAlreadyExists{trashed.Record.ID}
+		}
+		ids[trashed.Record.ID] = struct{}{}
+	}
+	return nil
+}
+
+// install replaces the content of the manager with a checked state and
+// claims its VINs. s.mu must be held for writing and the VINs of the
+// previous content released.
+func (s *manager) install(state State) {
+	s.records = make(map[string]car.Record, len(state.Records))
+	s.trash = make(map[string]TrashedRecord, len(state.Trash))
+	s.indexes = newIndexes()
+	for _, record := range state.Records {
+		s.records[record.ID] = record
+		s.indexes.add(record)
+		s.vins.claim(record.VIN, record.ID)
+	}
+	for _, trashed := range state.Trash {
+		s.trash[trashed.Record.ID] = trashed
+	}
+
+	s.history = make(map[string][]car.StatusChange, len(state.StatusHistory))
+	for id, changes := range state.StatusHistory {
+		s.history[id] = slices.Clone(changes)
+	}
+	s.prices = make(map[string][]car.PriceChange, len(state.Prices))
+	for id, changes := range state.Prices {
+		s.prices[id] = slices.Clone(changes)
+		var drop priceDrop
+		dropped := false
+		for i := 1; i < len(changes); i++ {
+			drop, dropped = nextDrop(drop, dropped, changes[i-1].Price, changes[i].Price, changes[i].At)
+		}
+		if dropped {
+			s.indexes.setDrop(id, drop, true)
+		}
+	}
+}
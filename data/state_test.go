@@ -0,0 +1,71 @@
+package data
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/clock"
+)
+
+func TestManager_DumpLoad(t *testing.T) {
+	ctx := context.Background()
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	testManager := NewManager(WithClock(fake))
+
+	record := car.Record{
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     2020,
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+	}
+	for _, id := range []string{"1", "2", "3"} {
+		record.ID = id
+		_ = testManager.Add(ctx, record)
+	}
+	fake.Advance(time.Hour)
+	record.ID = "1"
+	record.Price = car.NewMoney(9000, "USD")
+	_ = testManager.Update(ctx, record)
+	_, _ = testManager.Transition(ctx, "2", car.StatusReserved)
+	_ = testManager.Delete(ctx, "3")
+
+	state := mustDump(t, testManager)
+
+	// A bad bulk edit after the dump is rolled back by loading it.
+	_ = testManager.Delete(ctx, "1")
+	_, _ = testManager.Transition(ctx, "2", car.StatusSold)
+
+	if err := testManager.Load(ctx, state); err != nil {
+		t.Fatalf("unexpected error loading state: %v", err)
+	}
+	if got := mustDump(t, testManager); !reflect.DeepEqual(got, state) {
+		t.Fatalf("unexpected state after load, wanted: %v, got: %v", state, got)
+	}
+	if got := recordIDs(mustQuery(t, testManager, Query{Status: car.StatusReserved})); !reflect.DeepEqual(got, []string{"2"}) {
+		t.Fatalf("unexpected status index after load: %v", got)
+	}
+	if got := recordIDs(mustQuery(t, testManager, Query{PriceDroppedSince: fake.Now()})); !reflect.DeepEqual(got, []string{"1"}) {
+		t.Fatalf("unexpected price drop index after load: %v", got)
+	}
+	if got := mustTrash(t, testManager); len(got) != 1 || got[0].Record.ID != "3" {
+		t.Fatalf("unexpected trash after load: %v", got)
+	}
+
+	duplicate := State{Records: []car.Record{record, record}}
+	err := testManager.Load(ctx, duplicate)
+	wantErr := ErrorAlreadyExists{record.ID}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+	if got := mustDump(t, testManager); !reflect.DeepEqual(got, state) {
+		t.Fatalf("expected failed load to leave the manager untouched, got: %v", got)
+	}
+}
@@ -0,0 +1,44 @@
+package data
+
+import (
+	"context"
+	"slices"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+// Transition moves a car to another status if the move is allowed from its
+// current one.
+func (s *manager) Transition(ctx context.Context, carID string, to car.Status) (car.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return car.Record{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, exists := s.records[carID]
+	if !exists {
+		return car.Record{}, ErrorRecordNotFound{carID}
+	}
+	if !record.Status.CanTransitionTo(to) {
+		return car.Record{}, ErrorIllegalTransition{carID, record.Status, to}
+	}
+
+	record.Status = to
+	s.storeRecord(record)
+	return record, nil
+}
+
+// StatusHistory returns every status a car went through, oldest first.
+func (s *manager) StatusHistory(ctx context.Context, carID string) ([]car.StatusChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, exists := s.records[carID]; !exists {
+		return nil, ErrorRecordNotFound{carID}
+	}
+	return slices.Clone(s.history[carID]), nil
+}
@@ -0,0 +1,126 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+func TestManager_Transition(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+
+	record := car.Record{
+		ID:       "123",
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     time.Now().Year(),
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+	}
+	if err := testManager.Add(ctx, record); err != nil {
+		t.Fatalf("unexpected error adding record: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		to      car.Status
+		wantErr error
+	}{
+		{"reserve", "123", car.StatusReserved, nil},
+		{"reserved cannot go to service", "123", car.StatusInService, ErrorIllegalTransition{"123", car.StatusReserved, car.StatusInService}},
+		{"sell", "123", car.StatusSold, nil},
+		{"sold is final", "123", car.StatusAvailable, ErrorIllegalTransition{"123", car.StatusSold, car.StatusAvailable}},
+		{"unknown car", "456", car.StatusReserved, ErrorRecordNotFound{"456"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testManager.Transition(ctx, tt.id, tt.to)
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Status != tt.to {
+				t.Fatalf("unexpected status, wanted: %s, got: %s", tt.to, got.Status)
+			}
+		})
+	}
+
+	if got := recordIDs(mustQuery(t, testManager, Query{Status: car.StatusSold})); len(got) != 1 {
+		t.Fatalf("expected sold car to be found by status, got: %v", got)
+	}
+
+	history, err := testManager.StatusHistory(ctx, "123")
+	if err != nil {
+		t.Fatalf("unexpected error getting history: %v", err)
+	}
+	wantStatuses := []car.Status{car.StatusAvailable, car.StatusReserved, car.StatusSold}
+	if len(history) != len(wantStatuses) {
+		t.Fatalf("unexpected history length, wanted: %d, got: %v", len(wantStatuses), history)
+	}
+	for i, change := range history {
+		if change.To != wantStatuses[i] {
+			t.Fatalf("unexpected history entry %d, wanted: %s, got: %s", i, wantStatuses[i], change.To)
+		}
+		if i > 0 && change.From != wantStatuses[i-1] {
+			t.Fatalf("unexpected history entry %d, wanted from: %s, got: %s", i, wantStatuses[i-1], change.From)
+		}
+	}
+
+	if _, err := testManager.StatusHistory(ctx, "456"); err == nil {
+		t.Fatalf("expected error getting history of unknown car")
+	}
+}
+
+func TestManager_UpdateStatus(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+
+	record := car.Record{
+		ID:       "123",
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     time.Now().Year(),
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+	}
+	_ = testManager.Add(ctx, record)
+
+	// An empty status keeps the current one.
+	record.Color = "Red"
+	if err := testManager.Update(ctx, record); err != nil {
+		t.Fatalf("unexpected error updating record: %v", err)
+	}
+	if got, _ := testManager.Get(ctx, record.ID); got.Status != car.StatusAvailable {
+		t.Fatalf("unexpected status, wanted: %s, got: %s", car.StatusAvailable, got.Status)
+	}
+
+	record.Status = car.StatusSold
+	if err := testManager.Update(ctx, record); err != nil {
+		t.Fatalf("unexpected error selling record: %v", err)
+	}
+
+	record.Status = car.StatusReserved
+	err := testManager.Update(ctx, record)
+	wantErr := ErrorIllegalTransition{record.ID, car.StatusSold, car.StatusReserved}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+}
@@ -0,0 +1,116 @@
+package data
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+// TrashedRecord is a deleted car, kept until restored or purged.
+type TrashedRecord struct {
+	Record    car.Record `json:"car"`
+	DeletedAt time.Time  `json:"deleted_at"`
+}
+
+/*
+Delete moves a car to the trash. It is hidden from every lookup and its
+VIN may be taken by another car, but it keeps its ID, status and price
+history until purged.
+*/
+func (s *manager) Delete(ctx context.Context, carID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.trashRecord(carID)
+}
+
+// trashRecord moves a record to the trash. s.mu must be held for writing.
+func (s *manager) trashRecord(carID string) error {
+	record, exists := s.records[carID]
+	if !exists {
+		return ErrorRecordNotFound{carID}
+	}
+
+	s.indexes.remove(record)
+	s.vins.release(record.VIN, carID)
+	delete(s.records, carID)
+	s.trash[carID] = TrashedRecord{record, s.clock.Now()}
+	return nil
+}
+
+// Trash lists the deleted cars, the most recently deleted first.
+func (s *manager) Trash(ctx context.Context) ([]TrashedRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]TrashedRecord, 0, len(s.trash))
+	for _, trashed := range s.trash {
+		list = append(list, trashed)
+	}
+	slices.SortFunc(list, compareTrashed)
+	return list, nil
+}
+
+// compareTrashed orders the most recently deleted records first.
+func compareTrashed(a, b TrashedRecord) int {
+	if c := b.DeletedAt.Compare(a.DeletedAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Record.ID, b.Record.ID)
+}
+
+// Restore takes a car out of the trash as it was when deleted.
+func (s *manager) Restore(ctx context.Context, carID string) (car.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return car.Record{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trashed, exists := s.trash[carID]
+	if !exists {
+		return car.Record{}, ErrorNotInTrash{carID}
+	}
+	record := trashed.Record
+	if err := s.claimVIN(record); err != nil {
+		return car.Record{}, err
+	}
+
+	delete(s.trash, carID)
+	s.records[carID] = record
+	s.indexes.add(record)
+	return record, nil
+}
+
+// Purge permanently removes the cars deleted at least retention ago and
+// returns their IDs.
+func (s *manager) Purge(ctx context.Context, retention time.Duration) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := s.clock.Now().Add(-retention)
+	var purged []string
+	for id, trashed := range s.trash {
+		if trashed.DeletedAt.After(cutoff) {
+			continue
+		}
+		delete(s.trash, id)
+		delete(s.history, id)
+		delete(s.prices, id)
+		s.indexes.setDrop(id, priceDrop{}, false)
+		purged = append(purged, id)
+	}
+	slices.Sort(purged)
+	return purged, nil
+}
@@ -0,0 +1,160 @@
+package data
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/clock"
+)
+
+func TestManager_Trash(t *testing.T) {
+	ctx := context.Background()
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	testManager := NewManager(WithClock(fake))
+
+	record := car.Record{
+		ID:       "123",
+		Make:     "Honda",
+		Model:    "Accord",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     2003,
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+		VIN:      "1HGCM82633A004352",
+		Status:   car.StatusAvailable,
+	}
+	_ = testManager.Add(ctx, record)
+
+	if err := testManager.Delete(ctx, "123"); err != nil {
+		t.Fatalf("unexpected error deleting: %v", err)
+	}
+
+	if _, err := testManager.Get(ctx, "123"); err == nil {
+		t.Fatalf("expected trashed record to be hidden from Get")
+	}
+	if got := mustList(t, testManager); len(got) != 0 {
+		t.Fatalf("expected trashed record to be hidden from List, got: %v", got)
+	}
+	if _, err := testManager.GetByVIN(ctx, record.VIN); err == nil {
+		t.Fatalf("expected trashed record to be hidden from GetByVIN")
+	}
+
+	want := []TrashedRecord{{record, fake.Now()}}
+	if got := mustTrash(t, testManager); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected trash, wanted: %v, got: %v", want, got)
+	}
+
+	err := testManager.Add(ctx, record)
+	wantErr := ErrorAlreadyExists{"123"}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error reusing a trashed ID, wanted: %v, got: %v", wantErr, err)
+	}
+
+	restored, err := testManager.Restore(ctx, "123")
+	if err != nil || !reflect.DeepEqual(restored, record) {
+		t.Fatalf("unexpected restore, wanted: %v, got: %v, error: %v", record, restored, err)
+	}
+	if got, err := testManager.GetByVIN(ctx, record.VIN); err != nil || !reflect.DeepEqual(got, record) {
+		t.Fatalf("unexpected lookup after restore, wanted: %v, got: %v, error: %v", record, got, err)
+	}
+	if history, _ := testManager.StatusHistory(ctx, "123"); len(history) != 1 {
+		t.Fatalf("expected status history to survive the trash, got: %v", history)
+	}
+
+	_, err = testManager.Restore(ctx, "123")
+	wantErr2 := ErrorNotInTrash{"123"}
+	if err == nil || err.Error() != wantErr2.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr2, err)
+	}
+
+	err = testManager.Delete(ctx, "456")
+	wantErr3 := ErrorRecordNotFound{"456"}
+	if err == nil || err.Error() != wantErr3.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr3, err)
+	}
+}
+
+func TestManager_RestoreVINTaken(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := NewManager()
+
+	record := car.Record{
+		ID:       "123",
+		Make:     "Honda",
+		Model:    "Accord",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     2003,
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+		VIN:      "1HGCM82633A004352",
+	}
+	_ = testManager.Add(ctx, record)
+	_ = testManager.Delete(ctx, "123")
+
+	record.ID = "456"
+	if err := testManager.Add(ctx, record); err != nil {
+		t.Fatalf("expected VIN of a trashed car to be reusable, got: %v", err)
+	}
+
+	_, err := testManager.Restore(ctx, "123")
+	wantErr := ErrorVINAlreadyExists{record.VIN}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+}
+
+func TestManager_Purge(t *testing.T) {
+	ctx := context.Background()
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	testManager := NewManager(WithClock(fake))
+
+	record := car.Record{
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     2020,
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+	}
+	for _, id := range []string{"old", "recent", "kept"} {
+		record.ID = id
+		_ = testManager.Add(ctx, record)
+	}
+
+	_ = testManager.Delete(ctx, "old")
+	fake.Advance(20 * 24 * time.Hour)
+	_ = testManager.Delete(ctx, "recent")
+	fake.Advance(10 * 24 * time.Hour)
+
+	purged, err := testManager.Purge(ctx, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error purging: %v", err)
+	}
+	if !reflect.DeepEqual(purged, []string{"old"}) {
+		t.Fatalf("unexpected purged records, wanted: %v, got: %v", []string{"old"}, purged)
+	}
+	if got := mustTrash(t, testManager); len(got) != 1 || got[0].Record.ID != "recent" {
+		t.Fatalf("unexpected trash after purge: %v", got)
+	}
+
+	// A purged ID is free again.
+	record.ID = "old"
+	if err := testManager.Add(ctx, record); err != nil {
+		t.Fatalf("expected purged ID to be reusable, got: %v", err)
+	}
+	if history, _ := testManager.StatusHistory(ctx, "old"); len(history) != 1 {
+		t.Fatalf("expected purged history to be dropped, got: %v", history)
+	}
+}
@@ -0,0 +1,159 @@
+package data
+
+import (
+	"context"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+// Tx is the store as seen from within a transaction.
+type Tx interface {
+	Get(carID string) (car.Record, error)
+	Add(car.Record) error
+	Update(car.Record) error
+	Delete(carID string) error
+}
+
+/*
+Tx runs fn with the store locked for writing, so transactions are
+serializable, and applies its changes only if fn returns nil. When fn
+fails or panics every change it made is rolled back. fn must not call the
+manager itself, only the Tx it is given.
+*/
+func (s *manager) Tx(ctx context.Context, fn func(Tx) error) (err error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := &tx{m: s, saved: make(map[string]bool)}
+	defer func() {
+		if r := recover(); r != nil {
+			t.rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(t); err != nil {
+		t.rollback()
+		return err
+	}
+	return nil
+}
+
+// undoEntry is everything a transaction may change about one car.
+type undoEntry struct {
+	id       string
+	record   car.Record
+	exists   bool
+	trashed  TrashedRecord
+	inTrash  bool
+	history  int
+	prices   int
+	lastDrop priceDrop
+	dropped  bool
+}
+
+type tx struct {
+	m     *manager
+	undo  []undoEntry
+	saved map[string]bool
+}
+
+func (t *tx) Get(carID string) (car.Record, error) {
+	record, exists := t.m.records[carID]
+	if !exists {
+		return car.Record{}, ErrorRecordNotFound{carID}
+	}
+	return record, nil
+}
+
+func (t *tx) Add(record car.Record) error {
+	record, err := t.m.validate(record)
+	if err != nil {
+		return err
+	}
+	t.save(record.ID)
+	return t.m.insert(record)
+}
+
+func (t *tx) Update(record car.Record) error {
+	record, err := t.m.validate(record)
+	if err != nil {
+		return err
+	}
+	t.save(record.ID)
+	return t.m.replace(record)
+}
+
+func (t *tx) Delete(carID string) error {
+	t.save(carID)
+	return t.m.trashRecord(carID)
+}
+
+// save records the state of a car before the transaction first changes it.
+func (t *tx) save(id string) {
+	if t.saved[id] {
+		return
+	}
+	t.saved[id] = true
+
+	m := t.m
+	entry := undoEntry{id: id, history: len(m.history[id]), prices: len(m.prices[id])}
+	entry.record, entry.exists = m.records[id]
+	entry.trashed, entry.inTrash = m.trash[id]
+	entry.lastDrop, entry.dropped = m.indexes.lastDrop[id]
+	t.undo = append(t.undo, entry)
+}
+
+/*
+rollback puts every saved car back as it was. All current records leave
+the indexes before any saved one is put back, as cars may have traded
+unique values such as their VIN during the transaction.
+*/
+func (t *tx) rollback() {
+	t.unstore()
+	t.restore()
+}
+
+// unstore takes every car changed by the transaction out of the store.
+func (t *tx) unstore() {
+	m := t.m
+	for _, entry := range t.undo {
+		if current, exists := m.records[entry.id]; exists {
+			m.indexes.remove(current)
+			m.vins.release(current.VIN, current.ID)
+			delete(m.records, entry.id)
+		}
+		m.indexes.setDrop(entry.id, priceDrop{}, false)
+	}
+}
+
+// restore puts back every saved car once unstore took them out.
+func (t *tx) restore() {
+	m := t.m
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		entry := t.undo[i]
+		if entry.exists {
+			m.records[entry.id] = entry.record
+			m.indexes.add(entry.record)
+			m.vins.claim(entry.record.VIN, entry.id)
+		}
+		delete(m.trash, entry.id)
+		if entry.inTrash {
+			m.trash[entry.id] = entry.trashed
+		}
+		if entry.dropped {
+			m.indexes.setDrop(entry.id, entry.lastDrop, true)
+		}
+		m.history[entry.id] = m.history[entry.id][:entry.history]
+		if entry.history == 0 {
+			delete(m.history, entry.id)
+		}
+		m.prices[entry.id] = m.prices[entry.id][:entry.prices]
+		if entry.prices == 0 {
+			delete(m.prices, entry.id)
+		}
+	}
+}
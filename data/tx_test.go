@@ -0,0 +1,209 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+func newTxTestManager(t *testing.T) Manager {
+	ctx := context.Background()
+
+	t.Helper()
+	testManager := NewManager()
+
+	for _, record := range []car.Record{
+		{ID: "1", Price: car.NewMoney(10000, "USD"), VIN: "1HGCM82633A004352"},
+		{ID: "2", Price: car.NewMoney(20000, "USD"), VIN: ""},
+		{ID: "3", Price: car.NewMoney(30000, "USD"), VIN: ""},
+	} {
+		record.Make = "Honda"
+		record.Model = "Accord"
+		record.Category = "Sedan"
+		record.Package = "Standard"
+		record.Color = "Blue"
+		record.Year = 2003
+		record.Mileage = 1000
+		if err := testManager.Add(ctx, record); err != nil {
+			t.Fatalf("unexpected error adding record: %v", err)
+		}
+	}
+	return testManager
+}
+
+func TestManager_TxCommit(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := newTxTestManager(t)
+
+	// Swap the prices of two cars and move one to another category.
+	err := testManager.Tx(ctx, func(tx Tx) error {
+		one, err := tx.Get("1")
+		if err != nil {
+			return err
+		}
+		two, err := tx.Get("2")
+		if err != nil {
+			return err
+		}
+		one.Price, two.Price = two.Price, one.Price
+		two.Category = "Coupe"
+		if err := tx.Update(one); err != nil {
+			return err
+		}
+		return tx.Update(two)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error committing: %v", err)
+	}
+
+	one, _ := testManager.Get(ctx, "1")
+	two, _ := testManager.Get(ctx, "2")
+	if one.Price != car.NewMoney(20000, "USD") || two.Price != car.NewMoney(10000, "USD") {
+		t.Fatalf("expected prices to be swapped, got: %v and %v", one.Price, two.Price)
+	}
+	if got := recordIDs(mustQuery(t, testManager, Query{Category: "Coupe"})); !reflect.DeepEqual(got, []string{"2"}) {
+		t.Fatalf("unexpected category index after commit: %v", got)
+	}
+}
+
+func TestManager_TxRollback(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := newTxTestManager(t)
+	before := mustDump(t, testManager)
+
+	wantErr := errors.New("abort")
+	err := testManager.Tx(ctx, func(tx Tx) error {
+		// Move the VIN from one car to another, then fail.
+		one, _ := tx.Get("1")
+		vin := one.VIN
+		one.VIN = ""
+		one.Price = car.NewMoney(5000, "USD")
+		if err := tx.Update(one); err != nil {
+			return err
+		}
+		two, _ := tx.Get("2")
+		two.VIN = vin
+		if err := tx.Update(two); err != nil {
+			return err
+		}
+		if _, err := tx.Get("3"); err != nil {
+			return err
+		}
+		if err := tx.Delete("3"); err != nil {
+			return err
+		}
+		if err := tx.Add(car.Record{ID: "4", Make: "Honda", Model: "Civic", Category: "Sedan", Package: "Standard", Color: "Red", Year: 2003, Mileage: 10, Price: car.NewMoney(1, "USD")}); err != nil {
+			return err
+		}
+		return wantErr
+	})
+	if err != wantErr {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+
+	if got := mustDump(t, testManager); !reflect.DeepEqual(got, before) {
+		t.Fatalf("expected rollback to restore the store, wanted: %v, got: %v", before, got)
+	}
+	if got, err := testManager.GetByVIN(ctx, "1HGCM82633A004352"); err != nil || got.ID != "1" {
+		t.Fatalf("expected VIN index to be restored, got: %v, error: %v", got, err)
+	}
+	if got := recordIDs(mustQuery(t, testManager, Query{Currency: "USD", Price: Range{Max: intPtr(5000)}})); len(got) != 0 {
+		t.Fatalf("expected price index to be restored, got: %v", got)
+	}
+	if err := testManager.Add(ctx, car.Record{ID: "4", Make: "Honda", Model: "Civic", Category: "Sedan", Package: "Standard", Color: "Red", Year: 2003, Mileage: 10, Price: car.NewMoney(1, "USD")}); err != nil {
+		t.Fatalf("expected rolled back add to leave its ID free, got: %v", err)
+	}
+}
+
+func TestManager_TxFailedOperation(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := newTxTestManager(t)
+	before := mustDump(t, testManager)
+
+	err := testManager.Tx(ctx, func(tx Tx) error {
+		if err := tx.Delete("1"); err != nil {
+			return err
+		}
+		return tx.Delete("missing")
+	})
+	wantErr := ErrorRecordNotFound{"missing"}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+	if got := mustDump(t, testManager); !reflect.DeepEqual(got, before) {
+		t.Fatalf("expected failed operation to roll back the transaction, got: %v", got)
+	}
+}
+
+func TestManager_TxPanic(t *testing.T) {
+	ctx := context.Background()
+
+	testManager := newTxTestManager(t)
+	before := mustDump(t, testManager)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected panic to propagate")
+			}
+		}()
+		_ = testManager.Tx(ctx, func(tx Tx) error {
+			_ = tx.Delete("1")
+			panic("boom")
+		})
+	}()
+
+	if got := mustDump(t, testManager); !reflect.DeepEqual(got, before) {
+		t.Fatalf("expected panic to roll back the transaction, got: %v", got)
+	}
+}
+
+func TestManager_TxManyAdds(t *testing.T) {
+	ctx := context.Background()
+
+	for name, testManager := range map[string]Manager{
+		"manager": newTxTestManager(t),
+		"sharded": NewShardedManager(4),
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Add cars cheapest last, then reprice and delete some in the
+			// same transaction.
+			err := testManager.Tx(ctx, func(tx Tx) error {
+				for i := 100; i > 10; i-- {
+					record := car.Record{ID: fmt.Sprintf("car-%03d", i), Make: "Honda", Model: "Civic", Category: "Sedan", Package: "Standard", Color: "Red", Year: 2000 + i%20, Mileage: i, Price: car.NewMoney(i, "EUR")}
+					if err := tx.Add(record); err != nil {
+						return err
+					}
+				}
+				record, _ := tx.Get("car-050")
+				record.Price = car.NewMoney(1, "EUR")
+				if err := tx.Update(record); err != nil {
+					return err
+				}
+				return tx.Delete("car-011")
+			})
+			if err != nil {
+				t.Fatalf("unexpected error committing: %v", err)
+			}
+
+			got := recordIDs(mustQuery(t, testManager, Query{Currency: "EUR", SortBy: SortByPrice, Limit: 3}))
+			if want := []string{"car-050", "car-012", "car-013"}; !reflect.DeepEqual(got, want) {
+				t.Fatalf("unexpected price order, wanted: %v, got: %v", want, got)
+			}
+			got = recordIDs(mustQuery(t, testManager, Query{Currency: "EUR", Price: Range{Min: intPtr(9800), Max: intPtr(10000)}, SortBy: SortByPrice}))
+			if want := []string{"car-098", "car-099", "car-100"}; !reflect.DeepEqual(got, want) {
+				t.Fatalf("unexpected price range, wanted: %v, got: %v", want, got)
+			}
+			if got := mustQuery(t, testManager, Query{Currency: "EUR", SortBy: SortByMileage, Desc: true, Limit: 1}); len(got) != 1 || got[0].ID != "car-100" {
+				t.Fatalf("unexpected mileage order: %v", recordIDs(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package exchange
+
+import "fmt"
+
+type ErrorNoRate struct {
+	From string
+	To   string
+}
+
+func (e ErrorNoRate) Error() string {
+	return fmt.Sprintf("no exchange rate from '%s' to '%s'", e.From, e.To)
+}
+
+type ErrorInvalidRate struct {
+	Currency string
+	Rate     string
+}
+
+func (e ErrorInvalidRate) Error() string {
+	return fmt.Sprintf("exchange rate for '%s' invalid value: '%s'", e.Currency, e.Rate)
+}
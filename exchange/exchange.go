@@ -0,0 +1,118 @@
+package exchange
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+/*
+Rates is a locally configured exchange-rate table. Every rate is the
+amount of a currency worth one unit of Base. Rates are kept as exact
+fractions so conversions only round once, to the minor unit of the
+target currency.
+*/
+type Rates struct {
+	Base  string
+	rates map[string]*big.Rat
+}
+
+type ratesFile struct {
+	Base  string                 `json:"base"`
+	Rates map[string]json.Number `json:"rates"`
+}
+
+/*
+Load reads a JSON rate table such as:
+
+	{"base": "USD", "rates": {"CAD": 1.36, "EUR": "0.92"}}
+*/
+func Load(path string) (*Rates, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var file ratesFile
+	decoder := json.NewDecoder(f)
+	decoder.UseNumber()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]string, len(file.Rates))
+	for code, rate := range file.Rates {
+		rates[code] = rate.String()
+	}
+	return New(file.Base, rates)
+}
+
+// New builds a rate table from decimal rates relative to base.
+func New(base string, rates map[string]string) (*Rates, error) {
+	base = strings.ToUpper(base)
+	if _, ok := car.CurrencyExponent(base); !ok {
+		return nil, ErrorInvalidRate{base, "1"}
+	}
+
+	r := &Rates{
+		Base:  base,
+		rates: map[string]*big.Rat{base: big.NewRat(1, 1)},
+	}
+	for code, rate := range rates {
+		code = strings.ToUpper(code)
+		value, ok := new(big.Rat).SetString(rate)
+		if _, known := car.CurrencyExponent(code); !known || !ok || value.Sign() <= 0 {
+			return nil, ErrorInvalidRate{code, rate}
+		}
+		r.rates[code] = value
+	}
+	return r, nil
+}
+
+// Convert returns m in currency to, rounded half away from zero. A nil
+// table only converts a currency to itself.
+func (r *Rates) Convert(m car.Money, to string) (car.Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+	if r == nil {
+		return car.Money{}, ErrorNoRate{m.Currency, to}
+	}
+
+	fromRate, fromOK := r.rates[m.Currency]
+	toRate, toOK := r.rates[to]
+	if !fromOK || !toOK {
+		return car.Money{}, ErrorNoRate{m.Currency, to}
+	}
+	fromExponent, _ := car.CurrencyExponent(m.Currency)
+	toExponent, _ := car.CurrencyExponent(to)
+
+	// amount / 10^fromExponent / fromRate * toRate * 10^toExponent
+	value := new(big.Rat).SetInt64(int64(m.Amount))
+	value.Mul(value, toRate)
+	value.Quo(value, fromRate)
+	value.Mul(value, new(big.Rat).SetFrac(pow10(toExponent), pow10(fromExponent)))
+
+	return car.Money{Amount: int(round(value)), Currency: to}, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// round rounds a fraction half away from zero.
+func round(value *big.Rat) int64 {
+	num := new(big.Int).Abs(value.Num())
+	quotient, remainder := new(big.Int).QuoRem(num, value.Denom(), new(big.Int))
+	if remainder.Lsh(remainder, 1).Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if value.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+	return quotient.Int64()
+}
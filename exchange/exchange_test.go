@@ -0,0 +1,112 @@
+package exchange
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+func TestRates_Convert(t *testing.T) {
+	rates, err := New("USD", map[string]string{
+		"CAD": "1.36",
+		"EUR": "0.92",
+		"JPY": "149.5",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error building rates: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		money     car.Money
+		to        string
+		wantMoney car.Money
+		wantErr   error
+	}{
+		{
+			name:      "from base",
+			money:     car.Money{Amount: 10000, Currency: "USD"},
+			to:        "CAD",
+			wantMoney: car.Money{Amount: 13600, Currency: "CAD"},
+		},
+		{
+			name:      "to base rounds half away from zero",
+			money:     car.Money{Amount: 1, Currency: "EUR"},
+			to:        "USD",
+			wantMoney: car.Money{Amount: 1, Currency: "USD"},
+		},
+		{
+			name:      "between non base currencies",
+			money:     car.Money{Amount: 13600, Currency: "CAD"},
+			to:        "EUR",
+			wantMoney: car.Money{Amount: 9200, Currency: "EUR"},
+		},
+		{
+			name:      "to currency without minor unit",
+			money:     car.Money{Amount: 1000, Currency: "USD"},
+			to:        "JPY",
+			wantMoney: car.Money{Amount: 1495, Currency: "JPY"},
+		},
+		{
+			name:      "same currency",
+			money:     car.Money{Amount: 1000, Currency: "GBP"},
+			to:        "GBP",
+			wantMoney: car.Money{Amount: 1000, Currency: "GBP"},
+		},
+		{
+			name:    "missing rate",
+			money:   car.Money{Amount: 1000, Currency: "USD"},
+			to:      "GBP",
+			wantErr: ErrorNoRate{"USD", "GBP"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rates.Convert(tt.money, tt.to)
+			if err != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("unexpected error, wanted success, got: %v", err)
+				}
+				if err.Error() != tt.wantErr.Error() {
+					t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+				}
+				return
+			} else if tt.wantErr != nil {
+				t.Fatalf("unexpected success, expected error: %s", tt.wantErr.Error())
+			}
+			if got != tt.wantMoney {
+				t.Fatalf("unexpected conversion, wanted: %v, got: %v", tt.wantMoney, got)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(`{"base": "usd", "rates": {"EUR": 0.92, "CAD": "1.36"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rates, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error loading rates: %v", err)
+	}
+	if rates.Base != "USD" {
+		t.Fatalf("unexpected base, wanted: USD, got: %s", rates.Base)
+	}
+	if _, err := rates.Convert(car.Money{Amount: 100, Currency: "EUR"}, "CAD"); err != nil {
+		t.Fatalf("unexpected error converting: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"base": "USD", "rates": {"EUR": -1}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Load(path)
+	wantErr := ErrorInvalidRate{"EUR", "-1"}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+}
@@ -0,0 +1,192 @@
+// Package carfile reads and writes cars as JSON or CSV files, for
+// importing and exporting the inventory.
+package carfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+// Header names the columns of car CSV files. Prices are in the minor
+// unit of their currency, as in JSON.
+var Header = []string{
+	"id", "make", "model", "category", "package", "color", "year",
+	"mileage", "mileage_unit", "price_amount", "price_currency", "vin", "status",
+}
+
+type ErrorUnknownFormat struct {
+	Path string
+}
+
+func (e ErrorUnknownFormat) Error() string {
+	return fmt.Sprintf("unknown format of '%s', expected a .csv or .json file", e.Path)
+}
+
+type ErrorInvalidRow struct {
+	Line int
+	Err  error
+}
+
+func (e ErrorInvalidRow) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err.Error())
+}
+
+// Read reads a JSON array or CSV file of cars, by its extension.
+func Read(path string) ([]car.Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var records []car.Record
+		if err := json.NewDecoder(file).Decode(&records); err != nil {
+			return nil, fmt.Errorf("error decoding '%s': %w", path, err)
+		}
+		return records, nil
+	case ".csv":
+		return ReadCSV(file)
+	}
+	return nil, ErrorUnknownFormat{path}
+}
+
+// Write writes cars to a JSON or CSV file, by its extension.
+func Write(path string, records []car.Record) error {
+	var write func(io.Writer, []car.Record) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		write = writeJSON
+	case ".csv":
+		write = WriteCSV
+	default:
+		return ErrorUnknownFormat{path}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file, records); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func writeJSON(w io.Writer, records []car.Record) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// ReadCSV reads cars from CSV with a header row naming the columns of
+// Header, in any order. Missing columns are left empty.
+func ReadCSV(r io.Reader) ([]car.Record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []car.Record{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	records := []car.Record{}
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		record, err := parseRow(columns, row)
+		if err != nil {
+			return nil, ErrorInvalidRow{line, err}
+		}
+		records = append(records, record)
+	}
+}
+
+func parseRow(columns map[string]int, row []string) (car.Record, error) {
+	get := func(name string) string {
+		if i, exists := columns[name]; exists && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	getInt := func(name string) (int, error) {
+		raw := get(name)
+		if raw == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return 0, fmt.Errorf("column '%s' invalid value: '%s'", name, raw)
+		}
+		return n, nil
+	}
+
+	record := car.Record{
+		ID:          get("id"),
+		Make:        get("make"),
+		Model:       get("model"),
+		Category:    get("category"),
+		Package:     get("package"),
+		Color:       get("color"),
+		MileageUnit: car.DistanceUnit(get("mileage_unit")),
+		VIN:         get("vin"),
+		Status:      car.Status(get("status")),
+	}
+
+	var err error
+	if record.Year, err = getInt("year"); err != nil {
+		return car.Record{}, err
+	}
+	if record.Mileage, err = getInt("mileage"); err != nil {
+		return car.Record{}, err
+	}
+	if record.Price.Amount, err = getInt("price_amount"); err != nil {
+		return car.Record{}, err
+	}
+	record.Price.Currency = strings.ToUpper(get("price_currency"))
+	if record.Price.Currency == "" {
+		record.Price.Currency = car.DefaultCurrency
+	}
+	return record, nil
+}
+
+func WriteCSV(w io.Writer, records []car.Record) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(Header); err != nil {
+		return err
+	}
+	for _, record := range records {
+		err := writer.Write([]string{
+			record.ID, record.Make, record.Model, record.Category, record.Package, record.Color,
+			strconv.Itoa(record.Year), strconv.Itoa(record.Mileage), string(record.MileageUnit),
+			strconv.Itoa(record.Price.Amount), record.Price.Currency, record.VIN, string(record.Status),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,90 @@
+package carfile
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+var testRecords = []car.Record{
+	{
+		ID: "1", Make: "Toyota", Model: "Camry", Category: "Sedan", Package: "Standard", Color: "Blue",
+		Year: 2020, Mileage: 1000, Price: car.NewMoney(10000, "USD"), Status: car.StatusAvailable,
+	},
+	{
+		ID: "2", Make: "Honda", Model: "Civic, Type R", Category: "Hatchback", Package: "Sport", Color: "Red",
+		Year: 2003, Mileage: 500, MileageUnit: car.Kilometers, Price: car.NewMoney(30000, "EUR"), VIN: "1HGCM82633A004352",
+	},
+}
+
+func TestRecordFiles(t *testing.T) {
+	for _, name := range []string{"cars.csv", "cars.json", "CARS.CSV"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := Write(path, testRecords); err != nil {
+				t.Fatalf("unexpected error writing: %v", err)
+			}
+			got, err := Read(path)
+			if err != nil {
+				t.Fatalf("unexpected error reading: %v", err)
+			}
+			if !reflect.DeepEqual(got, testRecords) {
+				t.Fatalf("unexpected records, wanted: %v, got: %v", testRecords, got)
+			}
+		})
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []car.Record
+		wantErr error
+	}{
+		{
+			name: "columns in any order",
+			csv:  "make,id,price_amount,year\nToyota,1,1000000,2020\n",
+			want: []car.Record{{ID: "1", Make: "Toyota", Year: 2020, Price: car.NewMoney(10000, "USD")}},
+		},
+		{
+			name: "empty",
+			csv:  "",
+			want: []car.Record{},
+		},
+		{
+			name:    "invalid number",
+			csv:     "id,year\n1,2020\n2,soon\n",
+			wantErr: ErrorInvalidRow{3, nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadCSV(strings.NewReader(tt.csv))
+			if tt.wantErr != nil {
+				if rowErr, ok := err.(ErrorInvalidRow); !ok || rowErr.Line != tt.wantErr.(ErrorInvalidRow).Line {
+					t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("unexpected records, wanted: %v, got: %v, %v", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestRecordFilesUnknownFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cars.xml")
+	if err := Write(path, testRecords); err != (ErrorUnknownFormat{path}) {
+		t.Fatalf("unexpected error writing, got: %v", err)
+	}
+	_ = os.WriteFile(path, nil, 0o644)
+	if _, err := Read(path); err != (ErrorUnknownFormat{path}) {
+		t.Fatalf("unexpected error reading, got: %v", err)
+	}
+}
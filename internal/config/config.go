@@ -1 +1,130 @@
 package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Values of CARS_VALIDATION.
+const (
+	ValidateRequests  = "requests"
+	ValidateResponses = "responses"
+)
+
+const (
+	defaultReservationHold = 48 * time.Hour
+	defaultTrashRetention  = 30 * 24 * time.Hour
+	defaultSnapshotKeep    = 10
+)
+
+// Config holds the settings of the API, read from environment variables.
+type Config struct {
+	// ExchangeRatesFile is the JSON exchange-rate table used to convert
+	// prices, conversion is disabled when empty.
+	ExchangeRatesFile string
+	// RulesFile holds the validation rules of the dealership, only the
+	// built-in checks apply when empty.
+	RulesFile string
+	// VocabularyFile seeds the makes, models and categories cars are
+	// checked against.
+	VocabularyFile string
+	// ReservationHold is how long a car is held for a customer unless the
+	// reservation asks otherwise.
+	ReservationHold time.Duration
+	// StoreShards splits the in-memory store into this many independently
+	// locked shards, a single store is used when 0 or 1.
+	StoreShards int
+	// SnapshotDir is the directory snapshots of the store are written to,
+	// snapshots are disabled when empty.
+	SnapshotDir string
+	// SnapshotKeep is how many snapshots are kept, the oldest being
+	// removed as new ones are saved. Every snapshot is kept when 0.
+	SnapshotKeep int
+	// SnapshotInterval is how often the API saves a snapshot while
+	// serving, besides the one saved when it stops. It only saves when
+	// stopping when 0.
+	SnapshotInterval time.Duration
+	// AdminToken is the bearer token the admin endpoints require, they
+	// are not served when empty.
+	AdminToken string
+	// TrashRetention is how long deleted cars can be restored before
+	// they are purged.
+	TrashRetention time.Duration
+	// Validation checks requests against the OpenAPI document of the API
+	// when ValidateRequests, and responses too when ValidateResponses,
+	// which is meant for tests. Nothing is checked when empty.
+	Validation string
+	// MaxBodySize is the largest request body accepted in bytes, the
+	// API default applies when 0.
+	MaxBodySize int64
+}
+
+func Load() (Config, error) {
+	cfg := Config{
+		ExchangeRatesFile: os.Getenv("CARS_EXCHANGE_RATES_FILE"),
+		RulesFile:         os.Getenv("CARS_RULES_FILE"),
+		VocabularyFile:    os.Getenv("CARS_VOCABULARY_FILE"),
+		SnapshotDir:       os.Getenv("CARS_SNAPSHOT_DIR"),
+		AdminToken:        os.Getenv("CARS_ADMIN_TOKEN"),
+		Validation:        os.Getenv("CARS_VALIDATION"),
+	}
+
+	switch cfg.Validation {
+	case "", ValidateRequests, ValidateResponses:
+	default:
+		return Config{}, fmt.Errorf("invalid CARS_VALIDATION '%s'", cfg.Validation)
+	}
+
+	if raw := os.Getenv("CARS_STORE_SHARDS"); raw != "" {
+		shards, err := strconv.Atoi(raw)
+		if err != nil || shards < 0 {
+			return Config{}, fmt.Errorf("invalid CARS_STORE_SHARDS '%s'", raw)
+		}
+		cfg.StoreShards = shards
+	}
+
+	cfg.SnapshotKeep = defaultSnapshotKeep
+	if raw := os.Getenv("CARS_SNAPSHOT_KEEP"); raw != "" {
+		keep, err := strconv.Atoi(raw)
+		if err != nil || keep < 0 {
+			return Config{}, fmt.Errorf("invalid CARS_SNAPSHOT_KEEP '%s'", raw)
+		}
+		cfg.SnapshotKeep = keep
+	}
+
+	if raw := os.Getenv("CARS_MAX_BODY_SIZE"); raw != "" {
+		size, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || size <= 0 {
+			return Config{}, fmt.Errorf("invalid CARS_MAX_BODY_SIZE '%s'", raw)
+		}
+		cfg.MaxBodySize = size
+	}
+
+	var err error
+	if cfg.ReservationHold, err = duration("CARS_RESERVATION_HOLD", defaultReservationHold); err != nil {
+		return Config{}, err
+	}
+	if cfg.TrashRetention, err = duration("CARS_TRASH_RETENTION", defaultTrashRetention); err != nil {
+		return Config{}, err
+	}
+	if cfg.SnapshotInterval, err = duration("CARS_SNAPSHOT_INTERVAL", 0); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// duration reads a positive duration such as "48h" from the environment.
+func duration(name string, fallback time.Duration) (time.Duration, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s '%s'", name, raw)
+	}
+	return d, nil
+}
@@ -0,0 +1,11 @@
+package idempotency
+
+import "fmt"
+
+type ErrorKeyReused struct {
+	Key string
+}
+
+func (e ErrorKeyReused) Error() string {
+	return fmt.Sprintf("idempotency key '%s' was already used for a different request", e.Key)
+}
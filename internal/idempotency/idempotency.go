@@ -0,0 +1,126 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Response is the outcome of a request, stored to be replayed on retries.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+type entry struct {
+	fingerprint string
+	response    Response
+	created     time.Time
+	done        chan struct{}
+}
+
+/*
+Store remembers the responses of requests carrying an Idempotency-Key so
+a retried request gets the original result instead of being executed
+again. Keys expire after the configured TTL.
+*/
+type Store struct {
+	ttl         time.Duration
+	entries     map[string]*entry
+	lastExpired time.Time
+	mu          *sync.Mutex
+}
+
+func NewStore(ttl time.Duration) *Store {
+	return &Store{
+		ttl:     ttl,
+		entries: make(map[string]*entry),
+		mu:      &sync.Mutex{},
+	}
+}
+
+// Fingerprint identifies a request payload so a key cannot be reused for a
+// different request.
+func Fingerprint(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+/*
+Do runs fn once per key and returns its response, replayed reports whether
+the response comes from an earlier request. Concurrent calls with the same
+key wait for the first one to finish. Server errors are not stored so the
+request can be retried.
+*/
+func (s *Store) Do(key, fingerprint string, fn func() Response) (response Response, replayed bool, err error) {
+	s.mu.Lock()
+	s.expire()
+	if e, exists := s.entries[key]; exists && !s.expired(e) {
+		s.mu.Unlock()
+		if e.fingerprint != fingerprint {
+			return Response{}, false, ErrorKeyReused{key}
+		}
+		<-e.done
+		if e.response.Status == 0 {
+			// The first request failed and was forgotten, try again.
+			return s.Do(key, fingerprint, fn)
+		}
+		return e.response, true, nil
+	}
+
+	e := &entry{
+		fingerprint: fingerprint,
+		created:     time.Now(),
+		done:        make(chan struct{}),
+	}
+	s.entries[key] = e
+	s.mu.Unlock()
+
+	response = fn()
+
+	s.mu.Lock()
+	if response.Status >= http.StatusInternalServerError {
+		// The entry may have been cleared and the key taken again since.
+		if s.entries[key] == e {
+			delete(s.entries, key)
+		}
+	} else {
+		e.response = response
+	}
+	s.mu.Unlock()
+	close(e.done)
+
+	return response, false, nil
+}
+
+// Clear forgets every stored response, for when the data they were
+// computed from was replaced. Requests in flight finish normally.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = make(map[string]*entry)
+}
+
+// expire drops completed entries older than the TTL, at most once a
+// second. s.mu must be held.
+func (s *Store) expire() {
+	now := time.Now()
+	if now.Sub(s.lastExpired) < time.Second {
+		return
+	}
+	s.lastExpired = now
+
+	for key, e := range s.entries {
+		if s.expired(e) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// expired reports whether a completed entry outlived the TTL.
+func (s *Store) expired(e *entry) bool {
+	return e.response.Status != 0 && time.Since(e.created) >= s.ttl
+}
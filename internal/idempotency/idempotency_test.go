@@ -0,0 +1,105 @@
+package idempotency
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStore_Do(t *testing.T) {
+	store := NewStore(time.Hour)
+	calls := 0
+	fn := func() Response {
+		calls++
+		return Response{Status: http.StatusCreated, Body: []byte("created")}
+	}
+
+	first, replayed, err := store.Do("key", Fingerprint([]byte("body")), fn)
+	if err != nil || replayed {
+		t.Fatalf("unexpected first result, replayed: %v, error: %v", replayed, err)
+	}
+
+	second, replayed, err := store.Do("key", Fingerprint([]byte("body")), fn)
+	if err != nil || !replayed {
+		t.Fatalf("unexpected retry result, replayed: %v, error: %v", replayed, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected fn to run once, ran %d times", calls)
+	}
+	if string(second.Body) != string(first.Body) || second.Status != first.Status {
+		t.Fatalf("unexpected replayed response, wanted: %v, got: %v", first, second)
+	}
+
+	_, _, err = store.Do("key", Fingerprint([]byte("other body")), fn)
+	wantErr := ErrorKeyReused{"key"}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+}
+
+func TestStore_DoServerErrorNotStored(t *testing.T) {
+	store := NewStore(time.Hour)
+	status := http.StatusInternalServerError
+	fn := func() Response {
+		return Response{Status: status}
+	}
+
+	_, _, _ = store.Do("key", "fingerprint", fn)
+	status = http.StatusCreated
+	response, replayed, err := store.Do("key", "fingerprint", fn)
+	if err != nil || replayed || response.Status != http.StatusCreated {
+		t.Fatalf("expected failed request to be executed again, got: %v, replayed: %v, error: %v", response, replayed, err)
+	}
+}
+
+func TestStore_DoExpired(t *testing.T) {
+	store := NewStore(time.Millisecond)
+	fn := func() Response {
+		return Response{Status: http.StatusCreated}
+	}
+
+	_, _, _ = store.Do("key", "fingerprint", fn)
+	time.Sleep(2 * time.Millisecond)
+	if _, replayed, _ := store.Do("key", "other fingerprint", fn); replayed {
+		t.Fatal("expected expired key to be executed again")
+	}
+}
+
+func TestStore_Clear(t *testing.T) {
+	store := NewStore(time.Hour)
+	fn := func() Response {
+		return Response{Status: http.StatusCreated}
+	}
+
+	_, _, _ = store.Do("key", "fingerprint", fn)
+	store.Clear()
+	if _, replayed, err := store.Do("key", "other fingerprint", fn); err != nil || replayed {
+		t.Fatalf("expected cleared key to be executed again, replayed: %v, error: %v", replayed, err)
+	}
+}
+
+func TestStore_DoConcurrent(t *testing.T) {
+	store := NewStore(time.Hour)
+	var calls int32
+	fn := func() Response {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(time.Millisecond)
+		return Response{Status: http.StatusCreated}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _ = store.Do("key", "fingerprint", fn)
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("expected fn to run once, ran %d times", calls)
+	}
+}
@@ -0,0 +1,304 @@
+// Package openapi describes HTTP APIs as OpenAPI 3 documents, with the
+// schemas of Go types derived from their JSON struct tags.
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Version is the OpenAPI version of the documents of this package.
+const Version = "3.0.3"
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Server is a URL the paths of a document are relative to.
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path by lower case method.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Summary     string              `json:"summary,omitempty"`
+	Deprecated  bool                `json:"deprecated,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+	// Security lists the schemes of Components.SecuritySchemes accepted,
+	// by name, the operation being open to anyone when empty.
+	Security []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme is a way for clients to authenticate, such as a bearer
+// token in the Authorization header.
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Schema is the subset of JSON schema used to describe Go types.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// componentPrefix starts the references to the schemas of Components.
+const componentPrefix = "#/components/schemas/"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+/*
+Schemas derives the schemas of Go values from their types. Named structs
+become components referenced by name, their properties taken from the
+json tag of each field: fields tagged "-" are left out and fields without
+omitempty are required. A doc tag describes a field.
+*/
+type Schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+	enums      map[reflect.Type][]any
+	aliases    map[reflect.Type]reflect.Type
+	defined    map[reflect.Type]*Schema
+}
+
+func NewSchemas() *Schemas {
+	return &Schemas{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+		enums:      make(map[reflect.Type][]any),
+		aliases:    make(map[reflect.Type]reflect.Type),
+		defined:    make(map[reflect.Type]*Schema),
+	}
+}
+
+// Enum restricts the schema of the type of values, such as a string type
+// with constants, to values.
+func (s *Schemas) Enum(values ...any) {
+	if len(values) == 0 {
+		return
+	}
+	t := reflect.TypeOf(values[0])
+	s.enums[t] = append(s.enums[t], values...)
+}
+
+// Name sets the component name of the struct type of v, its Go name by
+// default.
+func (s *Schemas) Name(v any, name string) {
+	s.names[reflect.TypeOf(v)] = name
+}
+
+// Alias describes the type of v with the schema of the type of as, for
+// types whose JSON is made by hand.
+func (s *Schemas) Alias(v, as any) {
+	s.aliases[reflect.TypeOf(v)] = reflect.TypeOf(as)
+}
+
+// Define describes the type of v with schema, for types whose JSON is
+// made by hand and matches no Go type.
+func (s *Schemas) Define(v any, schema *Schema) {
+	s.defined[reflect.TypeOf(v)] = schema
+}
+
+// Of returns the schema of the type of v, a reference for named structs.
+func (s *Schemas) Of(v any) *Schema {
+	return s.schema(reflect.TypeOf(v))
+}
+
+// Components returns the schemas of the named structs seen so far.
+func (s *Schemas) Components() map[string]*Schema {
+	return s.components
+}
+
+// Ref returns a reference to a component.
+func Ref(name string) *Schema {
+	return &Schema{Ref: componentPrefix + name}
+}
+
+// Resolve returns the component a schema refers to, or the schema itself.
+func (d *Document) Resolve(schema *Schema) *Schema {
+	if name, ok := strings.CutPrefix(schema.Ref, componentPrefix); ok {
+		if component, exists := d.Components.Schemas[name]; exists {
+			return component
+		}
+	}
+	return schema
+}
+
+func (s *Schemas) schema(t reflect.Type) *Schema {
+	if schema, ok := s.defined[t]; ok {
+		copied := *schema
+		return &copied
+	}
+	if as, ok := s.aliases[t]; ok {
+		return s.schema(as)
+	}
+	if values, ok := s.enums[t]; ok {
+		schema := s.kind(t)
+		schema.Enum = values
+		return schema
+	}
+	if t.Kind() == reflect.Pointer {
+		schema := s.schema(t.Elem())
+		if schema.Ref != "" {
+			return schema
+		}
+		schema.Nullable = true
+		return schema
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if t.Kind() == reflect.Struct && t.Name() != "" {
+		return s.component(t)
+	}
+	return s.kind(t)
+}
+
+// component registers the schema of a named struct and refers to it.
+func (s *Schemas) component(t reflect.Type) *Schema {
+	name, named := s.names[t]
+	if _, seen := s.components[name]; named && seen {
+		return Ref(name)
+	}
+
+	if !named {
+		name = upperFirst(t.Name())
+		if _, taken := s.components[name]; taken {
+			// Another package has a type of that name.
+			pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+			name = upperFirst(pkg) + name
+		}
+		s.names[t] = name
+	}
+	// Register first so recursive types refer to themselves.
+	s.components[name] = &Schema{}
+	*s.components[name] = *s.kind(t)
+	return Ref(name)
+}
+
+// kind returns the schema of t by its kind, ignoring its name.
+func (s *Schemas) kind(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schema(t.Elem())}
+	case reflect.Struct:
+		return s.object(t)
+	}
+	return &Schema{}
+}
+
+// object returns the schema of a struct from the json tags of its fields.
+func (s *Schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name, options, _ := strings.Cut(tag, ",")
+		// Like encoding/json, embedded structs add their fields even when
+		// unexported.
+		embedded := field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct
+		if tag == "-" || !field.IsExported() && !embedded {
+			continue
+		}
+		if embedded {
+			fields := s.object(field.Type)
+			for property, propertySchema := range fields.Properties {
+				schema.Properties[property] = propertySchema
+			}
+			schema.Required = append(schema.Required, fields.Required...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := s.schema(field.Type)
+		if doc := field.Tag.Get("doc"); doc != "" && property.Ref == "" {
+			property.Description = doc
+		}
+		schema.Properties[name] = property
+		if !strings.Contains(","+options+",", ",omitempty,") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
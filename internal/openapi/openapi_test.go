@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type color string
+
+type base struct {
+	Created time.Time `json:"created"`
+}
+
+type part struct {
+	base
+	Name     string            `json:"name" doc:"Name of the part"`
+	Color    color             `json:"color,omitempty"`
+	Count    *int              `json:"count"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Parts    []part            `json:"parts,omitempty"`
+	Internal string            `json:"-"`
+	hidden   string
+}
+
+func TestSchemas(t *testing.T) {
+	schemas := NewSchemas()
+	schemas.Enum(color("red"), color("blue"))
+
+	if got := schemas.Of([]part{}); !reflect.DeepEqual(got, &Schema{Type: "array", Items: Ref("Part")}) {
+		t.Fatalf("unexpected schema: %+v", got)
+	}
+
+	want := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"created": {Type: "string", Format: "date-time"},
+			"name":    {Type: "string", Description: "Name of the part"},
+			"color":   {Type: "string", Enum: []any{color("red"), color("blue")}},
+			"count":   {Type: "integer", Nullable: true},
+			"tags":    {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+			"parts":   {Type: "array", Items: Ref("Part")},
+		},
+		Required: []string{"created", "name", "count"},
+	}
+	if got := schemas.Components()["Part"]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected component, wanted: %+v, got: %+v", want, got)
+	}
+}
+
+func TestSchemas_Name(t *testing.T) {
+	schemas := NewSchemas()
+	schemas.Name(base{}, "Timestamps")
+	if got := schemas.Of(&base{}); !reflect.DeepEqual(got, Ref("Timestamps")) {
+		t.Fatalf("unexpected schema: %+v", got)
+	}
+	if _, ok := schemas.Components()["Timestamps"]; !ok {
+		t.Fatal("expected the component under its given name")
+	}
+
+	document := Document{Components: Components{Schemas: schemas.Components()}}
+	if got := document.Resolve(Ref("Timestamps")); got.Type != "object" {
+		t.Fatalf("unexpected resolved schema: %+v", got)
+	}
+	if missing := Ref("Missing"); document.Resolve(missing) != missing {
+		t.Fatal("expected an unknown reference to resolve to itself")
+	}
+}
+
+type rawPart []byte
+
+type assembly struct {
+	Part *rawPart `json:"part"`
+}
+
+func TestSchemas_Alias(t *testing.T) {
+	schemas := NewSchemas()
+	schemas.Alias(rawPart{}, part{})
+	schemas.Of(assembly{})
+	want := &Schema{Type: "object", Properties: map[string]*Schema{"part": Ref("Part")}, Required: []string{"part"}}
+	if got := schemas.Components()["Assembly"]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected component, wanted: %+v, got: %+v", want, got)
+	}
+}
+
+func TestSchemas_Define(t *testing.T) {
+	schemas := NewSchemas()
+	number := &Schema{Type: "number"}
+	schemas.Define(rawPart{}, number)
+	schemas.Of(assembly{})
+	got := schemas.Components()["Assembly"].Properties["part"]
+	if want := (&Schema{Type: "number", Nullable: true}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected property, wanted: %+v, got: %+v", want, got)
+	}
+	if number.Nullable {
+		t.Fatal("expected the defined schema to be left untouched")
+	}
+}
@@ -0,0 +1,230 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrorInvalidValue reports a value that does not match its schema. Where
+// locates the value, such as "body.price.amount".
+type ErrorInvalidValue struct {
+	Where  string
+	Reason string
+}
+
+func (e ErrorInvalidValue) Error() string {
+	return fmt.Sprintf("%s: %s", e.Where, e.Reason)
+}
+
+// ValidateJSON checks that body is a single JSON value matching schema.
+func (d *Document) ValidateJSON(schema *Schema, body []byte, where string) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return ErrorInvalidValue{where, fmt.Sprintf("invalid JSON: %s", err.Error())}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return ErrorInvalidValue{where, "unexpected data after the JSON value"}
+	}
+	return d.Validate(schema, v, where)
+}
+
+/*
+ValidateParameter checks the raw value of a parameter, read from a query
+string or a header. A missing value, raw being empty, is only invalid
+when the parameter is required.
+*/
+func (d *Document) ValidateParameter(p Parameter, raw string) error {
+	where := fmt.Sprintf("%s parameter '%s'", p.In, p.Name)
+	if raw == "" {
+		if p.Required {
+			return ErrorInvalidValue{where, "missing"}
+		}
+		return nil
+	}
+
+	schema := d.Resolve(p.Schema)
+	var v any = raw
+	switch schema.Type {
+	case "integer", "number":
+		v = json.Number(raw)
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return ErrorInvalidValue{where, fmt.Sprintf("'%s' is not a boolean", raw)}
+		}
+		v = b
+	}
+	return d.Validate(schema, v, where)
+}
+
+// Validate checks a value decoded from JSON, with numbers as json.Number,
+// against schema.
+func (d *Document) Validate(schema *Schema, v any, where string) error {
+	schema = d.Resolve(schema)
+
+	if schema.OneOf != nil {
+		return d.validateOneOf(schema.OneOf, v, where)
+	}
+	if v == nil {
+		if schema.Nullable || schema.Type == "" {
+			return nil
+		}
+		return ErrorInvalidValue{where, fmt.Sprintf("expected %s, got null", schema.Type)}
+	}
+
+	if err := d.validateType(schema, v, where); err != nil {
+		return err
+	}
+	if schema.Enum != nil && !inEnum(schema.Enum, v) {
+		return ErrorInvalidValue{where, fmt.Sprintf("%v is not one of %v", v, schema.Enum)}
+	}
+	return nil
+}
+
+func (d *Document) validateType(schema *Schema, v any, where string) error {
+	mismatch := func() error {
+		return ErrorInvalidValue{where, fmt.Sprintf("expected %s, got %s", schema.Type, jsonType(v))}
+	}
+
+	switch schema.Type {
+	case "":
+		return nil
+	case "string":
+		if _, ok := v.(string); !ok {
+			return mismatch()
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return mismatch()
+		}
+	case "integer", "number":
+		n, ok := v.(json.Number)
+		if !ok {
+			return mismatch()
+		}
+		return validateNumber(schema, n, where)
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			return mismatch()
+		}
+		if schema.Items == nil {
+			return nil
+		}
+		for i, item := range items {
+			if err := d.Validate(schema.Items, item, fmt.Sprintf("%s[%d]", where, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		object, ok := v.(map[string]any)
+		if !ok {
+			return mismatch()
+		}
+		return d.validateObject(schema, object, where)
+	}
+	return nil
+}
+
+func validateNumber(schema *Schema, n json.Number, where string) error {
+	f, err := n.Float64()
+	if err != nil {
+		return ErrorInvalidValue{where, fmt.Sprintf("'%s' is not a number", n)}
+	}
+	if schema.Type == "integer" {
+		if _, err := n.Int64(); err != nil {
+			return ErrorInvalidValue{where, fmt.Sprintf("'%s' is not an integer", n)}
+		}
+	}
+	if schema.Minimum != nil && f < float64(*schema.Minimum) {
+		return ErrorInvalidValue{where, fmt.Sprintf("%s is less than %d", n, *schema.Minimum)}
+	}
+	return nil
+}
+
+func (d *Document) validateObject(schema *Schema, object map[string]any, where string) error {
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			return ErrorInvalidValue{where + "." + name, "missing"}
+		}
+	}
+
+	// Sorted so the first error reported does not change between calls.
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, known := schema.Properties[name]
+		if !known {
+			property = schema.AdditionalProperties
+		}
+		if property == nil {
+			continue
+		}
+		if err := d.Validate(property, object[name], where+"."+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateOneOf checks v matches exactly one of schemas.
+func (d *Document) validateOneOf(schemas []*Schema, v any, where string) error {
+	matches, reasons := 0, make([]string, 0, len(schemas))
+	for _, schema := range schemas {
+		if err := d.Validate(schema, v, where); err != nil {
+			if invalid := err.(ErrorInvalidValue); invalid.Where == where {
+				reasons = append(reasons, invalid.Reason)
+			} else {
+				reasons = append(reasons, invalid.Error())
+			}
+			continue
+		}
+		matches++
+	}
+	switch matches {
+	case 1:
+		return nil
+	case 0:
+		return ErrorInvalidValue{where, strings.Join(reasons, ", or ")}
+	}
+	return ErrorInvalidValue{where, "matches more than one schema"}
+}
+
+func inEnum(enum []any, v any) bool {
+	for _, value := range enum {
+		if fmt.Sprint(value) == fmt.Sprint(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType names the JSON type of a decoded value.
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
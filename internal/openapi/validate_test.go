@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"testing"
+)
+
+func TestDocument_ValidateJSON(t *testing.T) {
+	one := 1
+	document := Document{Components: Components{Schemas: map[string]*Schema{
+		"Money": {OneOf: []*Schema{
+			{Type: "object", Properties: map[string]*Schema{"amount": {Type: "integer"}}, Required: []string{"amount"}},
+			{Type: "integer"},
+		}},
+		"Car": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"make":   {Type: "string"},
+				"year":   {Type: "integer", Minimum: &one},
+				"status": {Type: "string", Enum: []any{"available", "sold"}},
+				"price":  Ref("Money"),
+				"tags":   {Type: "array", Items: &Schema{Type: "string"}},
+				"vin":    {Type: "string", Nullable: true},
+			},
+			Required: []string{"make"},
+		},
+	}}}
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr error
+	}{
+		{"valid", `{"make": "Toyota", "year": 2020, "status": "sold", "price": {"amount": 100}, "tags": ["a"], "vin": null}`, nil},
+		{"bare price", `{"make": "Toyota", "price": 100}`, nil},
+		{"unknown fields pass", `{"make": "Toyota", "colour": "red"}`, nil},
+		{"missing", `{"year": 2020}`, ErrorInvalidValue{"body.make", "missing"}},
+		{"wrong type", `{"make": 1}`, ErrorInvalidValue{"body.make", "expected string, got number"}},
+		{"fraction", `{"make": "Toyota", "year": 2020.5}`, ErrorInvalidValue{"body.year", "'2020.5' is not an integer"}},
+		{"minimum", `{"make": "Toyota", "year": 0}`, ErrorInvalidValue{"body.year", "0 is less than 1"}},
+		{"enum", `{"make": "Toyota", "status": "lost"}`, ErrorInvalidValue{"body.status", "lost is not one of [available sold]"}},
+		{"item", `{"make": "Toyota", "tags": ["a", 2]}`, ErrorInvalidValue{"body.tags[1]", "expected string, got number"}},
+		{"null", `{"make": null}`, ErrorInvalidValue{"body.make", "expected string, got null"}},
+		{"one of", `{"make": "Toyota", "price": "100"}`, ErrorInvalidValue{"body.price", "expected object, got string, or expected integer, got string"}},
+		{"trailing data", `{"make": "Toyota"} {}`, ErrorInvalidValue{"body", "unexpected data after the JSON value"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := document.ValidateJSON(Ref("Car"), []byte(tt.body), "body")
+			if err != tt.wantErr {
+				t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDocument_ValidateParameter(t *testing.T) {
+	var document Document
+	tests := []struct {
+		name    string
+		param   Parameter
+		raw     string
+		wantErr bool
+	}{
+		{"integer", Parameter{Name: "limit", In: "query", Schema: &Schema{Type: "integer"}}, "10", false},
+		{"not an integer", Parameter{Name: "limit", In: "query", Schema: &Schema{Type: "integer"}}, "ten", true},
+		{"boolean", Parameter{Name: "desc", In: "query", Schema: &Schema{Type: "boolean"}}, "true", false},
+		{"enum", Parameter{Name: "units", In: "header", Schema: &Schema{Type: "string", Enum: []any{"km"}}}, "mi", true},
+		{"optional", Parameter{Name: "id", In: "query", Schema: &Schema{Type: "string"}}, "", false},
+		{"required", Parameter{Name: "id", In: "query", Required: true, Schema: &Schema{Type: "string"}}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := document.ValidateParameter(tt.param, tt.raw); (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
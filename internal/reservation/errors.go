@@ -0,0 +1,38 @@
+package reservation
+
+import (
+	"fmt"
+	"time"
+)
+
+type ErrorAlreadyReserved struct {
+	CarID  string
+	Holder string
+	Until  time.Time
+}
+
+func (e ErrorAlreadyReserved) Error() string {
+	return fmt.Sprintf("car '%s' is reserved by '%s' until %s", e.CarID, e.Holder, e.Until.Format(time.RFC3339))
+}
+
+type ErrorNotReserved struct {
+	CarID string
+}
+
+func (e ErrorNotReserved) Error() string {
+	return fmt.Sprintf("car '%s' is not reserved", e.CarID)
+}
+
+type ErrorHolderMissing struct{}
+
+func (e ErrorHolderMissing) Error() string {
+	return "reservation holder missing"
+}
+
+type ErrorInvalidDuration struct {
+	Duration time.Duration
+}
+
+func (e ErrorInvalidDuration) Error() string {
+	return fmt.Sprintf("invalid reservation duration: '%s'", e.Duration)
+}
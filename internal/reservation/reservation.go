@@ -0,0 +1,257 @@
+package reservation
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+// Reservation is a hold on a car for a customer until it expires.
+type Reservation struct {
+	CarID  string    `json:"car_id"`
+	Holder string    `json:"holder"`
+	Until  time.Time `json:"until"`
+}
+
+/*
+Service holds cars for customers for a limited time. A car is moved to
+the reserved status while held and back to available when the hold is
+released or expires. A hold ends early when the status of the car changes
+some other way, e.g. when it is sold, even if it is reserved again later
+by other means.
+*/
+type Service struct {
+	cars  data.Manager
+	clock clock.Clock
+	holds map[string]hold
+	mu    *sync.Mutex
+}
+
+// hold is a reservation with the length of the status history of its car
+// once reserved, the hold lasting only as long as no change follows.
+type hold struct {
+	Reservation
+	changes int
+}
+
+func NewService(cars data.Manager, clk clock.Clock) *Service {
+	return &Service{
+		cars:  cars,
+		clock: clk,
+		holds: make(map[string]hold),
+		mu:    &sync.Mutex{},
+	}
+}
+
+/*
+Reserve holds a car for holder during d. The holder of a current
+reservation may extend it, anyone else gets ErrorAlreadyReserved.
+*/
+func (s *Service) Reserve(ctx context.Context, carID, holder string, d time.Duration) (Reservation, error) {
+	holder = strings.TrimSpace(holder)
+	if holder == "" {
+		return Reservation{}, ErrorHolderMissing{}
+	}
+	if d <= 0 {
+		return Reservation{}, ErrorInvalidDuration{d}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return Reservation{}, err
+	}
+
+	reservation := Reservation{CarID: carID, Holder: holder, Until: s.clock.Now().Add(d)}
+
+	if current, exists := s.holds[carID]; exists {
+		if s.active(ctx, current) {
+			if current.Holder != holder {
+				return Reservation{}, ErrorAlreadyReserved{carID, current.Holder, current.Until}
+			}
+			s.holds[carID] = hold{reservation, current.changes}
+			return reservation, nil
+		}
+		s.release(ctx, current)
+	}
+
+	if _, err := s.cars.Transition(ctx, carID, car.StatusReserved); err != nil {
+		return Reservation{}, err
+	}
+	history, err := s.cars.StatusHistory(ctx, carID)
+	if err != nil {
+		return Reservation{}, err
+	}
+	s.holds[carID] = hold{reservation, len(history)}
+	return reservation, nil
+}
+
+// Get returns the current reservation of a car.
+func (s *Service) Get(ctx context.Context, carID string) (Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return Reservation{}, err
+	}
+
+	hold, exists := s.holds[carID]
+	if !exists || !s.active(ctx, hold) {
+		return Reservation{}, ErrorNotReserved{carID}
+	}
+	return hold.Reservation, nil
+}
+
+// List returns the current reservations, the first to expire first.
+func (s *Service) List(ctx context.Context) ([]Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	list := make([]Reservation, 0, len(s.holds))
+	for _, hold := range s.holds {
+		if s.active(ctx, hold) {
+			list = append(list, hold.Reservation)
+		}
+	}
+	slices.SortFunc(list, func(a, b Reservation) int {
+		if c := a.Until.Compare(b.Until); c != 0 {
+			return c
+		}
+		return strings.Compare(a.CarID, b.CarID)
+	})
+	return list, nil
+}
+
+// Release ends the reservation of a car and makes it available again.
+func (s *Service) Release(ctx context.Context, carID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	hold, exists := s.holds[carID]
+	if !exists || !s.active(ctx, hold) {
+		return ErrorNotReserved{carID}
+	}
+	return s.release(ctx, hold)
+}
+
+// Sweep releases every expired reservation still holding its car and
+// returns them, forgetting the others. It stops early when ctx is done,
+// the remaining ones are released by a later sweep.
+func (s *Service) Sweep(ctx context.Context) []Reservation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []Reservation
+	for _, hold := range s.holds {
+		if ctx.Err() != nil {
+			break
+		}
+		if !s.expired(hold) {
+			continue
+		}
+		if err := s.release(ctx, hold); err == nil {
+			expired = append(expired, hold.Reservation)
+		}
+	}
+	return expired
+}
+
+/*
+Reset forgets every hold, for when the cars of the Service were replaced
+such as by a snapshot, and holds each reserved car for d on behalf of
+holder, so none stays reserved for good without a hold to expire. It
+returns the new holds.
+*/
+func (s *Service) Reset(ctx context.Context, holder string, d time.Duration) ([]Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.holds = make(map[string]hold)
+	reserved, err := s.cars.Query(ctx, data.Query{Status: car.StatusReserved})
+	if err != nil {
+		return nil, err
+	}
+	until := s.clock.Now().Add(d)
+	list := make([]Reservation, 0, len(reserved))
+	for _, record := range reserved {
+		history, err := s.cars.StatusHistory(ctx, record.ID)
+		if err != nil {
+			return nil, err
+		}
+		reservation := Reservation{CarID: record.ID, Holder: holder, Until: until}
+		s.holds[record.ID] = hold{reservation, len(history)}
+		list = append(list, reservation)
+	}
+	return list, nil
+}
+
+// Run sweeps expired reservations every interval of the clock of the
+// Service until ctx is done, calling expired, if not nil, with each one.
+func (s *Service) Run(ctx context.Context, interval time.Duration, expired func(Reservation)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(interval):
+			for _, hold := range s.Sweep(ctx) {
+				if expired != nil {
+					expired(hold)
+				}
+			}
+		}
+	}
+}
+
+func (s *Service) expired(hold hold) bool {
+	return !s.clock.Now().Before(hold.Until)
+}
+
+// active reports whether hold still holds its car. s.mu must be held.
+func (s *Service) active(ctx context.Context, hold hold) bool {
+	return !s.expired(hold) && s.holding(ctx, hold)
+}
+
+// holding reports whether the car of hold is still reserved by the change
+// the hold made, no other change of status having followed.
+func (s *Service) holding(ctx context.Context, hold hold) bool {
+	history, err := s.cars.StatusHistory(ctx, hold.CarID)
+	return err == nil && len(history) == hold.changes && history[len(history)-1].To == car.StatusReserved
+}
+
+// release forgets hold and makes its car available. It only forgets it
+// and returns ErrorNotReserved when the status of the car has changed
+// meanwhile. The hold is kept when ctx is done first. s.mu must be held.
+func (s *Service) release(ctx context.Context, hold hold) error {
+	if !s.holding(ctx, hold) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		delete(s.holds, hold.CarID)
+		return ErrorNotReserved{hold.CarID}
+	}
+
+	_, err := s.cars.Transition(ctx, hold.CarID, car.StatusAvailable)
+	if err != nil && err == ctx.Err() {
+		return err
+	}
+	delete(s.holds, hold.CarID)
+
+	if _, moved := err.(data.ErrorIllegalTransition); moved {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,276 @@
+package reservation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+func newTestService(t *testing.T) (*Service, data.Manager, *clock.Fake) {
+	ctx := context.Background()
+
+	t.Helper()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	cars := data.NewManager(data.WithClock(fake))
+
+	err := cars.Add(ctx, car.Record{
+		ID:       "123",
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     2020,
+		Mileage:  1000,
+		Price:    car.NewMoney(10000, "USD"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error adding record: %v", err)
+	}
+	return NewService(cars, fake), cars, fake
+}
+
+func carStatus(t *testing.T, cars data.Manager, id string) car.Status {
+	ctx := context.Background()
+
+	t.Helper()
+	record, err := cars.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("unexpected error getting record: %v", err)
+	}
+	return record.Status
+}
+
+func TestService_Reserve(t *testing.T) {
+	ctx := context.Background()
+
+	service, cars, fake := newTestService(t)
+
+	hold, err := service.Reserve(ctx, "123", "Alice", 48*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error reserving: %v", err)
+	}
+	if want := fake.Now().Add(48 * time.Hour); !hold.Until.Equal(want) {
+		t.Fatalf("unexpected expiry, wanted: %v, got: %v", want, hold.Until)
+	}
+	if got := carStatus(t, cars, "123"); got != car.StatusReserved {
+		t.Fatalf("unexpected status, wanted: %s, got: %s", car.StatusReserved, got)
+	}
+
+	_, err = service.Reserve(ctx, "123", "Bob", time.Hour)
+	wantErr := ErrorAlreadyReserved{"123", "Alice", hold.Until}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+
+	// The holder may extend their own reservation.
+	fake.Advance(24 * time.Hour)
+	extended, err := service.Reserve(ctx, "123", "Alice", 48*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error extending: %v", err)
+	}
+	if !extended.Until.After(hold.Until) {
+		t.Fatalf("expected reservation to be extended past %v, got: %v", hold.Until, extended.Until)
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		holder  string
+		d       time.Duration
+		wantErr error
+	}{
+		{"missing holder", "123", " ", time.Hour, ErrorHolderMissing{}},
+		{"invalid duration", "123", "Bob", 0, ErrorInvalidDuration{0}},
+		{"unknown car", "456", "Bob", time.Hour, data.ErrorRecordNotFound{ID: "456"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Reserve(ctx, tt.id, tt.holder, tt.d)
+			if err == nil || err.Error() != tt.wantErr.Error() {
+				t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestService_Sweep(t *testing.T) {
+	ctx := context.Background()
+
+	service, cars, fake := newTestService(t)
+
+	if _, err := service.Reserve(ctx, "123", "Alice", 48*time.Hour); err != nil {
+		t.Fatalf("unexpected error reserving: %v", err)
+	}
+
+	fake.Advance(47 * time.Hour)
+	if expired := service.Sweep(ctx); len(expired) != 0 {
+		t.Fatalf("unexpected expired reservations: %v", expired)
+	}
+	if got, err := service.List(ctx); err != nil || len(got) != 1 {
+		t.Fatalf("expected one reservation, got: %v, %v", got, err)
+	}
+
+	fake.Advance(time.Hour)
+	if _, err := service.Get(ctx, "123"); err == nil {
+		t.Fatalf("expected expired reservation not to be returned")
+	}
+	if expired := service.Sweep(ctx); len(expired) != 1 || expired[0].Holder != "Alice" {
+		t.Fatalf("unexpected expired reservations: %v", expired)
+	}
+	if got := carStatus(t, cars, "123"); got != car.StatusAvailable {
+		t.Fatalf("unexpected status, wanted: %s, got: %s", car.StatusAvailable, got)
+	}
+
+	history, _ := cars.StatusHistory(ctx, "123")
+	if last := history[len(history)-1]; !last.At.Equal(fake.Now()) {
+		t.Fatalf("unexpected release time, wanted: %v, got: %v", fake.Now(), last.At)
+	}
+
+	if _, err := service.Reserve(ctx, "123", "Bob", time.Hour); err != nil {
+		t.Fatalf("expected car to be reservable after expiry, got: %v", err)
+	}
+}
+
+func TestService_Release(t *testing.T) {
+	ctx := context.Background()
+
+	service, cars, _ := newTestService(t)
+
+	err := service.Release(ctx, "123")
+	wantErr := ErrorNotReserved{"123"}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+
+	_, _ = service.Reserve(ctx, "123", "Alice", time.Hour)
+	if err := service.Release(ctx, "123"); err != nil {
+		t.Fatalf("unexpected error releasing: %v", err)
+	}
+	if got := carStatus(t, cars, "123"); got != car.StatusAvailable {
+		t.Fatalf("unexpected status, wanted: %s, got: %s", car.StatusAvailable, got)
+	}
+}
+
+func TestService_SoldWhileReserved(t *testing.T) {
+	ctx := context.Background()
+
+	service, cars, fake := newTestService(t)
+
+	_, _ = service.Reserve(ctx, "123", "Alice", time.Hour)
+	if _, err := cars.Transition(ctx, "123", car.StatusSold); err != nil {
+		t.Fatalf("unexpected error selling: %v", err)
+	}
+
+	if got, err := service.List(ctx); err != nil || len(got) != 0 {
+		t.Fatalf("expected sale to end the reservation, got: %v, %v", got, err)
+	}
+
+	fake.Advance(time.Hour)
+	service.Sweep(ctx)
+	if got := carStatus(t, cars, "123"); got != car.StatusSold {
+		t.Fatalf("unexpected status after sweep, wanted: %s, got: %s", car.StatusSold, got)
+	}
+}
+
+func TestService_ReservedAgainElsewhere(t *testing.T) {
+	ctx := context.Background()
+
+	service, cars, fake := newTestService(t)
+
+	// Alice's hold ends when the car is released by a transition, and
+	// does not come back when the car is reserved again that way.
+	_, _ = service.Reserve(ctx, "123", "Alice", time.Hour)
+	if _, err := cars.Transition(ctx, "123", car.StatusAvailable); err != nil {
+		t.Fatalf("unexpected error releasing: %v", err)
+	}
+	if _, err := cars.Transition(ctx, "123", car.StatusReserved); err != nil {
+		t.Fatalf("unexpected error reserving again: %v", err)
+	}
+
+	if got, err := service.List(ctx); err != nil || len(got) != 0 {
+		t.Fatalf("expected the first reservation to be over, got: %v, %v", got, err)
+	}
+	fake.Advance(time.Hour)
+	if expired := service.Sweep(ctx); len(expired) != 0 {
+		t.Fatalf("unexpected expired reservations: %v", expired)
+	}
+	if got := carStatus(t, cars, "123"); got != car.StatusReserved {
+		t.Fatalf("expected the sweep to leave the other reservation, got: %s", got)
+	}
+}
+
+func TestService_Reset(t *testing.T) {
+	ctx := context.Background()
+
+	service, cars, fake := newTestService(t)
+
+	// The car comes back reserved from a state saved during Alice's hold,
+	// without the hold.
+	_, _ = service.Reserve(ctx, "123", "Alice", 48*time.Hour)
+	state, err := cars.Dump(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error dumping: %v", err)
+	}
+	_ = service.Release(ctx, "123")
+	if err := cars.Load(ctx, state); err != nil {
+		t.Fatalf("unexpected error loading: %v", err)
+	}
+
+	held, err := service.Reset(ctx, "snapshot", time.Hour)
+	if err != nil || len(held) != 1 || held[0].Holder != "snapshot" {
+		t.Fatalf("unexpected holds, got: %v, %v", held, err)
+	}
+	if _, err := service.Reserve(ctx, "123", "Bob", time.Hour); err == nil {
+		t.Fatal("expected the car to stay held until the hold expires")
+	}
+	fake.Advance(time.Hour)
+	if expired := service.Sweep(ctx); len(expired) != 1 {
+		t.Fatalf("expected the reset hold to expire, got: %v", expired)
+	}
+	if got := carStatus(t, cars, "123"); got != car.StatusAvailable {
+		t.Fatalf("unexpected status, wanted: %s, got: %s", car.StatusAvailable, got)
+	}
+}
+
+func TestService_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	service, cars, fake := newTestService(t)
+	_, _ = service.Reserve(ctx, "123", "Alice", time.Hour)
+
+	expired := make(chan Reservation, 1)
+	done := make(chan struct{})
+	go func() {
+		service.Run(ctx, time.Minute, func(hold Reservation) { expired <- hold })
+		close(done)
+	}()
+
+	// Only the fake clock moves the sweeps.
+	for waited := 0; fake.Waiters() == 0; waited++ {
+		if waited == 1000 {
+			t.Fatal("expected Run to wait on the clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	fake.Advance(time.Hour)
+	select {
+	case hold := <-expired:
+		if hold.Holder != "Alice" {
+			t.Fatalf("unexpected expired reservation: %v", hold)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the reservation to expire")
+	}
+	if got := carStatus(t, cars, "123"); got != car.StatusAvailable {
+		t.Fatalf("unexpected status, wanted: %s, got: %s", car.StatusAvailable, got)
+	}
+
+	cancel()
+	<-done
+}
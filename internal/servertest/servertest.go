@@ -0,0 +1,29 @@
+// Package servertest serves the routes of a server.Router in tests.
+package servertest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/YoungOak/GoAPI/server"
+)
+
+// Router is a server.Router serving from a server.Mux, which tests serve
+// themselves, such as with httptest.
+type Router struct {
+	*server.Mux
+}
+
+// NewRouter returns a Router with no routes.
+func NewRouter() Router {
+	return Router{server.NewMux()}
+}
+
+func (r Router) AddHandler(route string, handler http.HandlerFunc) {
+	r.HandleFunc(route, handler)
+}
+
+// Serve does nothing, the routes are served through the Mux.
+func (r Router) Serve(ctx context.Context) error {
+	return nil
+}
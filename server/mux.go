@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+/*
+Mux is an http.ServeMux that also matches patterns with path parameters,
+such as "/cars/{id}", each standing for one non-empty segment of the path.
+Patterns without parameters are served by the ServeMux and take
+precedence, between two patterns with parameters the one with a literal
+segment where the other has a parameter wins, from the left.
+*/
+type Mux struct {
+	static   *http.ServeMux
+	patterns []pattern
+}
+
+// pattern is a route with path parameters split into segments, the
+// parameters being the segments in braces.
+type pattern struct {
+	segments []string
+	handler  http.Handler
+}
+
+type pathValuesKey struct{}
+
+func NewMux() *Mux {
+	return &Mux{static: http.NewServeMux()}
+}
+
+func (m *Mux) Handle(route string, handler http.Handler) {
+	if !strings.Contains(route, "{") {
+		m.static.Handle(route, handler)
+		return
+	}
+	m.patterns = append(m.patterns, pattern{strings.Split(strings.Trim(route, "/"), "/"), handler})
+}
+
+func (m *Mux) HandleFunc(route string, handler http.HandlerFunc) {
+	m.Handle(route, handler)
+}
+
+func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, route := m.static.Handler(r); route != "" || len(m.patterns) == 0 {
+		m.static.ServeHTTP(w, r)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+	var best *pattern
+	for i := range m.patterns {
+		p := &m.patterns[i]
+		if p.matches(segments) && (best == nil || p.precedes(best)) {
+			best = p
+		}
+	}
+	if best == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	values := make(map[string]string)
+	for i, segment := range best.segments {
+		if name, ok := parameter(segment); ok {
+			values[name], _ = url.PathUnescape(segments[i])
+		}
+	}
+	best.handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), pathValuesKey{}, values)))
+}
+
+// PathValue returns the value of the path parameter name of the pattern
+// that matched r, or "" when it has none.
+func PathValue(r *http.Request, name string) string {
+	values, _ := r.Context().Value(pathValuesKey{}).(map[string]string)
+	return values[name]
+}
+
+func (p *pattern) matches(segments []string) bool {
+	if len(segments) != len(p.segments) {
+		return false
+	}
+	for i, segment := range p.segments {
+		if _, ok := parameter(segment); ok {
+			if segments[i] == "" {
+				return false
+			}
+		} else if segments[i] != segment {
+			return false
+		}
+	}
+	return true
+}
+
+// precedes reports whether p has a literal segment before other does.
+func (p *pattern) precedes(other *pattern) bool {
+	for i, segment := range p.segments {
+		_, isParameter := parameter(segment)
+		_, otherIsParameter := parameter(other.segments[i])
+		if isParameter != otherIsParameter {
+			return otherIsParameter
+		}
+	}
+	return false
+}
+
+func parameter(segment string) (string, bool) {
+	if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+		return segment[1 : len(segment)-1], true
+	}
+	return "", false
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// shutdownTimeout is how long the requests in flight may take to finish
+// once the server is stopping.
+const shutdownTimeout = 10 * time.Second
+
+type Router interface {
+	// AddHandler serves route with handler. Routes may have path
+	// parameters, read with PathValue, as Mux matches them.
+	AddHandler(route string, handler http.HandlerFunc)
+	// Serve serves the routes until it fails or ctx is done, then lets
+	// the requests in flight finish before returning.
+	Serve(ctx context.Context) error
+}
+
+type router struct {
+	address string
+	router  *Mux
+}
+
+func NewRouter(listenAddress string) Router {
+	return &router{
+		listenAddress,
+		NewMux(),
+	}
+}
+
+func (r *router) AddHandler(route string, handler http.HandlerFunc) {
+	r.router.HandleFunc(route, handler)
+}
+
+func (r *router) Serve(ctx context.Context) error {
+
+	server := http.Server{
+		Addr:    r.address,
+		Handler: r.router,
+	}
+	slog.Info("Starting server", "Address", r.address)
+	failed := make(chan error, 1)
+	go func() {
+		failed <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-failed:
+		return err
+	case <-ctx.Done():
+	}
+	slog.Info("Stopping server", "Address", r.address)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// group is a Router adding its routes to another under a prefix.
+type group struct {
+	parent Router
+	prefix string
+}
+
+// Group returns a Router adding its routes to router under prefix, such
+// as "/v1", and serving with it.
+func Group(router Router, prefix string) Router {
+	return group{router, strings.TrimSuffix(prefix, "/")}
+}
+
+func (g group) AddHandler(route string, handler http.HandlerFunc) {
+	g.parent.AddHandler(g.prefix+route, handler)
+}
+
+func (g group) Serve(ctx context.Context) error {
+	return g.parent.Serve(ctx)
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRouter_AddHandler(t *testing.T) {
+	r := NewRouter("")
+	handlerCalled := false
+	testHandler := func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+	}
+	r.AddHandler("/test", testHandler)
+
+	rType, _ := r.(*router)
+	server := httptest.NewServer(rType.router)
+	defer server.Close()
+
+	http.Get(server.URL + "/test")
+
+	if !handlerCalled {
+		t.Fatal("expected testHandler to be called")
+	}
+}
+
+// Optional: You can potentially skip this test, as it's more of an
+// assertion about the Go standard library's functionality than your own code.
+func TestRouter_Serve(t *testing.T) {
+	r := NewRouter(":8080")
+	rType, _ := r.(*router)
+	server := httptest.NewServer(rType.router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("could not make GET request: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status 404, got: %d", resp.StatusCode)
+	}
+}
+
+func TestRouter_ServeStops(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := NewRouter("127.0.0.1:0")
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- r.Serve(ctx)
+	}()
+	cancel()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatalf("unexpected error stopping: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Serve to return once ctx is done")
+	}
+}
+
+func TestMux_PathValues(t *testing.T) {
+	m := NewMux()
+	for _, route := range []string{"/cars", "/cars/trash", "/cars/{id}", "/cars/{id}/history", "/cars/trash/{id}/restore", "/cars/{id}/{field}"} {
+		route := route
+		m.HandleFunc(route, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(route + " " + PathValue(r, "id") + " " + PathValue(r, "field")))
+		})
+	}
+
+	for _, tt := range []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"/cars", http.StatusOK, "/cars  "},
+		{"/cars/trash", http.StatusOK, "/cars/trash  "},
+		{"/cars/123", http.StatusOK, "/cars/{id} 123 "},
+		{"/cars/a%2Fb", http.StatusOK, "/cars/{id} a/b "},
+		{"/cars/123/history", http.StatusOK, "/cars/{id}/history 123 "},
+		{"/cars/123/prices", http.StatusOK, "/cars/{id}/{field} 123 prices"},
+		{"/cars/trash/123/restore", http.StatusOK, "/cars/trash/{id}/restore 123 "},
+		{"/cars/123/history/more", http.StatusNotFound, ""},
+		{"/cars//history", http.StatusNotFound, ""},
+		{"/trucks/123", http.StatusNotFound, ""},
+	} {
+		t.Run(tt.path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			m.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got: %d", tt.wantCode, rr.Code)
+			}
+			if tt.wantCode == http.StatusOK && rr.Body.String() != tt.wantBody {
+				t.Fatalf("expected body %q, got: %q", tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestGroup(t *testing.T) {
+	r := NewRouter("")
+	v2 := Group(r, "/v2/")
+	v2.AddHandler("/cars/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(PathValue(r, "id")))
+	})
+
+	rType, _ := r.(*router)
+	for _, tt := range []struct {
+		path     string
+		wantCode int
+	}{
+		{"/v2/cars/123", http.StatusOK},
+		{"/cars/123", http.StatusNotFound},
+		{"/v1/cars/123", http.StatusNotFound},
+	} {
+		rr := httptest.NewRecorder()
+		rType.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rr.Code != tt.wantCode {
+			t.Fatalf("%s: expected status %d, got: %d", tt.path, tt.wantCode, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package snapshot
+
+import "fmt"
+
+type ErrorSnapshotNotFound struct {
+	Name string
+}
+
+func (e ErrorSnapshotNotFound) Error() string {
+	return fmt.Sprintf("no snapshot named '%s'", e.Name)
+}
+
+type ErrorUnsupportedVersion struct {
+	Name    string
+	Version int
+}
+
+func (e ErrorUnsupportedVersion) Error() string {
+	return fmt.Sprintf("snapshot '%s' has unsupported version %d", e.Name, e.Version)
+}
+
+type ErrorCorruptSnapshot struct {
+	Name   string
+	Reason string
+}
+
+func (e ErrorCorruptSnapshot) Error() string {
+	return fmt.Sprintf("snapshot '%s' is corrupt: %s", e.Name, e.Reason)
+}
@@ -0,0 +1,194 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+// Version is the snapshot format written by this package. Loading rejects
+// other versions rather than guessing at their layout.
+const Version = 1
+
+const (
+	prefix     = "snapshot-"
+	extension  = ".json"
+	nameLayout = "20060102T150405.000000000Z"
+)
+
+// file is the layout of a snapshot on disk.
+type file struct {
+	Version int        `json:"version"`
+	TakenAt time.Time  `json:"taken_at"`
+	State   data.State `json:"state"`
+}
+
+// Info describes a snapshot without loading its content.
+type Info struct {
+	Name    string    `json:"name"`
+	TakenAt time.Time `json:"taken_at"`
+	Size    int64     `json:"size"`
+}
+
+// Store keeps snapshots as JSON files in a local directory.
+type Store struct {
+	dir   string
+	clock clock.Clock
+	// keep is how many snapshots Save leaves, all of them when 0.
+	keep int
+}
+
+type Option func(*Store)
+
+// WithRetention makes Save remove the oldest snapshots past the keep most
+// recent ones, they are all kept when keep is 0.
+func WithRetention(keep int) Option {
+	return func(s *Store) {
+		s.keep = keep
+	}
+}
+
+// NewStore creates dir if needed.
+func NewStore(dir string, clk clock.Clock, opts ...Option) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &Store{dir: dir, clock: clk}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+/*
+Save writes state to a new snapshot named after the time it is taken.
+The file is written under a temporary name and renamed once complete,
+so a crash never leaves a truncated snapshot behind. The snapshots past
+the retention are removed then, an error doing so is returned with the
+Info of the saved snapshot.
+*/
+func (s *Store) Save(state data.State) (Info, error) {
+	takenAt := s.clock.Now().UTC()
+	name := prefix + takenAt.Format(nameLayout) + extension
+
+	b, err := json.Marshal(file{Version, takenAt, state})
+	if err != nil {
+		return Info{}, err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".tmp-"+name)
+	if err != nil {
+		return Info{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return Info{}, err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return Info{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		return Info{}, err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, name)); err != nil {
+		return Info{}, err
+	}
+
+	info := Info{Name: name, TakenAt: takenAt, Size: int64(len(b))}
+	if s.keep > 0 {
+		if _, err := s.Prune(s.keep); err != nil {
+			return info, err
+		}
+	}
+	return info, nil
+}
+
+// Prune removes the snapshots past the keep most recent ones and returns
+// them.
+func (s *Store) Prune(keep int) ([]Info, error) {
+	list, err := s.List()
+	if err != nil || len(list) <= keep {
+		return nil, err
+	}
+	for i, info := range list[keep:] {
+		if err := os.Remove(filepath.Join(s.dir, info.Name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return list[keep : keep+i], err
+		}
+	}
+	return list[keep:], nil
+}
+
+// List returns the snapshots of the store, the most recent first.
+func (s *Store) List() ([]Info, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Info, 0, len(entries))
+	for _, entry := range entries {
+		takenAt, ok := parseName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		fileInfo, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, Info{Name: entry.Name(), TakenAt: takenAt, Size: fileInfo.Size()})
+	}
+	slices.SortFunc(list, func(a, b Info) int {
+		return b.TakenAt.Compare(a.TakenAt)
+	})
+	return list, nil
+}
+
+// Load reads the state saved in the named snapshot.
+func (s *Store) Load(name string) (data.State, error) {
+	if _, ok := parseName(name); !ok {
+		return data.State{}, ErrorSnapshotNotFound{name}
+	}
+
+	b, err := os.ReadFile(filepath.Join(s.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return data.State{}, ErrorSnapshotNotFound{name}
+	}
+	if err != nil {
+		return data.State{}, err
+	}
+
+	var snapshot file
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return data.State{}, ErrorCorruptSnapshot{name, err.Error()}
+	}
+	if snapshot.Version != Version {
+		return data.State{}, ErrorUnsupportedVersion{name, snapshot.Version}
+	}
+	return snapshot.State, nil
+}
+
+// parseName returns the time a snapshot was taken from its file name,
+// rejecting anything that is not a snapshot such as paths.
+func parseName(name string) (time.Time, bool) {
+	stamp, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	stamp, ok = strings.CutSuffix(stamp, extension)
+	if !ok {
+		return time.Time{}, false
+	}
+	takenAt, err := time.Parse(nameLayout, stamp)
+	return takenAt, err == nil
+}
@@ -0,0 +1,126 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+func TestStore(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	store, err := NewStore(filepath.Join(t.TempDir(), "snapshots"), fake)
+	if err != nil {
+		t.Fatalf("unexpected error creating store: %v", err)
+	}
+
+	first := data.State{
+		Records: []car.Record{{ID: "123", Make: "Toyota", Price: car.NewMoney(10000, "USD")}},
+	}
+	firstInfo, err := store.Save(first)
+	if err != nil {
+		t.Fatalf("unexpected error saving snapshot: %v", err)
+	}
+
+	fake.Advance(time.Hour)
+	second := data.State{}
+	secondInfo, err := store.Save(second)
+	if err != nil {
+		t.Fatalf("unexpected error saving snapshot: %v", err)
+	}
+
+	list, err := store.List()
+	if err != nil {
+		t.Fatalf("unexpected error listing snapshots: %v", err)
+	}
+	want := []Info{secondInfo, firstInfo}
+	if !reflect.DeepEqual(list, want) {
+		t.Fatalf("unexpected snapshots, wanted: %v, got: %v", want, list)
+	}
+
+	got, err := store.Load(firstInfo.Name)
+	if err != nil {
+		t.Fatalf("unexpected error loading snapshot: %v", err)
+	}
+	if !reflect.DeepEqual(got, first) {
+		t.Fatalf("unexpected state, wanted: %v, got: %v", first, got)
+	}
+}
+
+func TestStore_Retention(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	store, err := NewStore(t.TempDir(), fake, WithRetention(3))
+	if err != nil {
+		t.Fatalf("unexpected error creating store: %v", err)
+	}
+
+	var saved []Info
+	for i := 0; i < 5; i++ {
+		info, err := store.Save(data.State{})
+		if err != nil {
+			t.Fatalf("unexpected error saving snapshot: %v", err)
+		}
+		saved = append([]Info{info}, saved...)
+		fake.Advance(time.Minute)
+	}
+
+	list, err := store.List()
+	if err != nil {
+		t.Fatalf("unexpected error listing snapshots: %v", err)
+	}
+	if want := saved[:3]; !reflect.DeepEqual(list, want) {
+		t.Fatalf("unexpected snapshots kept, wanted: %v, got: %v", want, list)
+	}
+
+	removed, err := store.Prune(1)
+	if err != nil {
+		t.Fatalf("unexpected error pruning snapshots: %v", err)
+	}
+	if want := saved[1:3]; !reflect.DeepEqual(removed, want) {
+		t.Fatalf("unexpected snapshots removed, wanted: %v, got: %v", want, removed)
+	}
+	if _, err := store.Load(saved[1].Name); err != (ErrorSnapshotNotFound{saved[1].Name}) {
+		t.Fatalf("expected pruned snapshot to be gone, got: %v", err)
+	}
+}
+
+func TestStore_LoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewStore(dir, clock.System)
+
+	unsupported := "snapshot-20240101T120000.000000000Z.json"
+	_ = os.WriteFile(filepath.Join(dir, unsupported), []byte(`{"version": 99, "state": {}}`), 0o644)
+	corrupt := "snapshot-20240102T120000.000000000Z.json"
+	_ = os.WriteFile(filepath.Join(dir, corrupt), []byte(`{"version": 1, "sta`), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a snapshot"), 0o644)
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr error
+	}{
+		{"unsupported version", unsupported, ErrorUnsupportedVersion{unsupported, 99}},
+		{"corrupt", corrupt, ErrorCorruptSnapshot{corrupt, "unexpected end of JSON input"}},
+		{"missing", "snapshot-20240103T120000.000000000Z.json", ErrorSnapshotNotFound{"snapshot-20240103T120000.000000000Z.json"}},
+		{"not a snapshot", "notes.txt", ErrorSnapshotNotFound{"notes.txt"}},
+		{"path", "../" + unsupported, ErrorSnapshotNotFound{"../" + unsupported}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := store.Load(tt.file)
+			if err == nil || err.Error() != tt.wantErr.Error() {
+				t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	list, _ := store.List()
+	if len(list) != 2 {
+		t.Fatalf("expected only snapshot files to be listed, got: %v", list)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Package external checks that modules outside of GoAPI can use its public
+packages, which they could not if an exported signature named a type of
+an internal package.
+*/
+package external
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// buildModule builds the program in testdata/name as the module of
+// another repository, requiring GoAPI from this tree.
+func buildModule(t *testing.T, name string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("builds a module")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	source, err := os.ReadFile(filepath.Join("testdata", name, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	goMod := fmt.Sprintf("module example.com/%s\n\ngo 1.21\n\nrequire github.com/YoungOak/GoAPI v0.0.0\n\nreplace github.com/YoungOak/GoAPI => %s\n", name, root)
+	for file, content := range map[string][]byte{"go.mod": []byte(goMod), "main.go": source} {
+		if err := os.WriteFile(filepath.Join(dir, file), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command(goBin, "build", "-o", os.DevNull, ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("building %s outside of the module: %v\n%s", name, err, out)
+	}
+}
+
+func TestEmbedder(t *testing.T) {
+	buildModule(t, "embedder")
+}
+
+func TestSDK(t *testing.T) {
+	buildModule(t, "sdk")
+}
@@ -0,0 +1,56 @@
+// Command embedder serves the Cars API next to routes of its own, as a
+// module outside of GoAPI would.
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/YoungOak/GoAPI/api"
+	"github.com/YoungOak/GoAPI/clock"
+	"github.com/YoungOak/GoAPI/data"
+	"github.com/YoungOak/GoAPI/exchange"
+	"github.com/YoungOak/GoAPI/server"
+	"github.com/YoungOak/GoAPI/snapshot"
+	"github.com/YoungOak/GoAPI/vocab"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	registry := vocab.NewRegistry()
+	var cars data.Manager = data.NewShardedManager(4, data.WithVocabulary(registry))
+	rates, err := exchange.New("USD", map[string]string{"EUR": "0.9"})
+	if err != nil {
+		panic(err)
+	}
+	snapshots, err := snapshot.NewStore(os.TempDir(), clock.System)
+	if err != nil {
+		panic(err)
+	}
+
+	handler := api.NewHandler(cars, slog.Default(), clock.System,
+		api.WithVocabulary(registry),
+		api.WithExchangeRates(rates),
+		api.WithSnapshots(snapshots),
+		api.WithAdminToken("secret"),
+		api.WithReservationHold(24*time.Hour),
+	)
+
+	router := server.NewRouter(":8080")
+	handler.Register(server.Group(router, "/inventory"))
+	router.AddHandler("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	go handler.SweepReservations(ctx, time.Minute)
+	go handler.PurgeTrash(ctx, 30*24*time.Hour, time.Hour)
+	if err := router.Serve(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,61 @@
+// Command sdk calls the Cars API with the Go client, as a module outside
+// of GoAPI would.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/client"
+	"github.com/YoungOak/GoAPI/data"
+)
+
+func main() {
+	ctx := context.Background()
+	cars := client.New("http://localhost:8080", client.WithRetries(3, 100*time.Millisecond), client.WithToken("secret"))
+
+	record, err := cars.Add(ctx, car.Record{
+		Make:     "Toyota",
+		Model:    "Camry",
+		Category: "Sedan",
+		Package:  "Standard",
+		Color:    "Blue",
+		Year:     2020,
+		Mileage:  1000,
+		Price:    car.NewMoney(20000, "USD"),
+	})
+	var invalid car.ErrorFieldInvalid
+	if errors.As(err, &invalid) {
+		fmt.Fprintf(os.Stderr, "invalid %s\n", invalid.Field)
+		os.Exit(1)
+	}
+
+	record.Color = "Red"
+	if err := cars.Update(ctx, record); errors.As(err, new(data.ErrorRecordNotFound)) {
+		os.Exit(1)
+	}
+
+	maxPrice := 2500000
+	records, err := cars.List(ctx, data.Query{Currency: "USD", Price: data.Range{Max: &maxPrice}, SortBy: data.SortByPrice})
+	var unexpected client.ErrorUnexpectedStatus
+	if errors.As(err, &unexpected) {
+		fmt.Fprintln(os.Stderr, unexpected.Status)
+	}
+	fmt.Println(len(records))
+
+	it := cars.Iterate(data.Query{Make: "Toyota", Status: car.StatusAvailable}, 100)
+	for it.Next(ctx) {
+		fmt.Println(it.Record().ID)
+	}
+	if err := it.Err(); err != nil {
+		os.Exit(1)
+	}
+
+	if _, err := cars.Get(ctx, record.ID); err == nil {
+		_ = cars.Delete(ctx, record.ID)
+	}
+}
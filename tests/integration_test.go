@@ -1,32 +1,25 @@
 package integration
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+	"github.com/YoungOak/GoAPI/client"
+	"github.com/YoungOak/GoAPI/data"
 )
 
 const baseURL = "http://localhost:8080"
 
-type CarRecord struct {
-	ID       string `json:"id"`
-	Make     string `json:"make"`
-	Model    string `json:"model"`
-	Category string `json:"category"`
-	Package  string `json:"package"`
-	Color    string `json:"color"`
-	Year     int    `json:"year"`
-	Mileage  int    `json:"mileage"`
-	Price    int    `json:"price"`
-}
-
 func TestCarsAPIIntegration(t *testing.T) {
+	ctx := context.Background()
+	cars := client.New(baseURL)
+
 	// 1. POST a new car
-	newCar := CarRecord{
+	newCar := car.Record{
 		ID:       "test-car-1",
 		Make:     "TestMake",
 		Model:    "TestModel",
@@ -35,45 +28,26 @@ func TestCarsAPIIntegration(t *testing.T) {
 		Color:    "Blue",
 		Year:     2022,
 		Mileage:  0,
-		Price:    25000,
-	}
-
-	data, err := json.Marshal(newCar)
-	if err != nil {
-		t.Fatalf("Failed to marshal car data: %v", err)
+		Price:    car.NewMoney(2500000, "USD"),
 	}
 
-	resp, err := http.Post(fmt.Sprintf("%s/car", baseURL), "application/json", bytes.NewBuffer(data))
-	if err != nil {
+	if _, err := cars.Add(ctx, newCar); err != nil {
 		t.Fatalf("Failed to POST new car: %v", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusAccepted {
-		t.Fatalf("Expected status code %d, got %d", http.StatusAccepted, resp.StatusCode)
-	}
 
 	// 2. GET the list of cars and check our car
-	resp, err = http.Get(fmt.Sprintf("%s/cars", baseURL))
+	list, err := cars.List(ctx, data.Query{})
 	if err != nil {
 		t.Fatalf("Failed to GET cars: %v", err)
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	var cars []CarRecord
-	err = json.Unmarshal(body, &cars)
-	if err != nil {
-		t.Fatalf("Failed to unmarshal response: %v", err)
-	}
 
-	if len(cars) == 0 {
+	if len(list) == 0 {
 		t.Fatal("No cars found")
 	}
 
 	found := false
-	for _, car := range cars {
-		if car.ID == newCar.ID {
+	for _, record := range list {
+		if record.ID == newCar.ID {
 			found = true
 			break
 		}
@@ -84,45 +58,44 @@ func TestCarsAPIIntegration(t *testing.T) {
 	}
 
 	// 3. GET specific car by ID
-	resp, err = http.Get(fmt.Sprintf("%s/car?id=%s", baseURL, newCar.ID))
+	record, err := cars.Get(ctx, newCar.ID)
 	if err != nil {
 		t.Fatalf("Failed to GET car by ID: %v", err)
 	}
-	defer resp.Body.Close()
 
-	body, _ = io.ReadAll(resp.Body)
-	var car CarRecord
-	err = json.Unmarshal(body, &car)
-	if err != nil {
-		t.Fatalf("Failed to unmarshal response: %v", err)
-	}
-
-	if car.ID != newCar.ID {
-		t.Fatalf("Expected car ID %s, got %s", newCar.ID, car.ID)
+	if record.ID != newCar.ID {
+		t.Fatalf("Expected car ID %s, got %s", newCar.ID, record.ID)
 	}
 
 	// 4. PUT to update car and validate
 	newCar.Color = "Red"
-	data, err = json.Marshal(newCar)
-	if err != nil {
-		t.Fatalf("Failed to marshal car data for update: %v", err)
-	}
-
-	client := &http.Client{}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/car", baseURL), bytes.NewBuffer(data))
-	if err != nil {
-		t.Fatalf("Failed to create PUT request: %v", err)
+	if err := cars.Update(ctx, newCar); err != nil {
+		t.Fatalf("Failed to PUT update for car: %v", err)
 	}
 
-	resp, err = client.Do(req)
+	// 5. GET the car as clients predating versions do, with an integer
+	// price
+	resp, err := http.Get(baseURL + "/cars/" + newCar.ID)
 	if err != nil {
-		t.Fatalf("Failed to PUT update for car: %v", err)
+		t.Fatalf("Failed to GET unversioned car: %v", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusAccepted {
-		t.Fatalf("Expected status code %d for update, got %d", http.StatusAccepted, resp.StatusCode)
+	var legacy LegacyCarRecord
+	if err := json.NewDecoder(resp.Body).Decode(&legacy); err != nil {
+		t.Fatalf("Failed to decode unversioned car: %v", err)
 	}
+	if legacy.Price != 2500000 || legacy.Color != "Red" {
+		t.Fatalf("Unexpected unversioned car: %+v", legacy)
+	}
+}
+
+// LegacyCarRecord is a car as clients written before prices had a
+// currency read it.
+type LegacyCarRecord struct {
+	ID    string `json:"id"`
+	Color string `json:"color"`
+	Price int    `json:"price"`
 }
 
 func TestMain(m *testing.M) {
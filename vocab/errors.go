@@ -0,0 +1,29 @@
+package vocab
+
+import "fmt"
+
+type ErrorTermExists struct {
+	Kind string
+	Name string
+}
+
+func (e ErrorTermExists) Error() string {
+	return fmt.Sprintf("%s '%s' already exists", e.Kind, e.Name)
+}
+
+type ErrorTermNotFound struct {
+	Kind string
+	Name string
+}
+
+func (e ErrorTermNotFound) Error() string {
+	return fmt.Sprintf("%s '%s' not found", e.Kind, e.Name)
+}
+
+type ErrorTermEmpty struct {
+	Kind string
+}
+
+func (e ErrorTermEmpty) Error() string {
+	return fmt.Sprintf("%s name missing", e.Kind)
+}
@@ -0,0 +1,315 @@
+package vocab
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+const (
+	KindMake     = "make"
+	KindModel    = "model"
+	KindCategory = "category"
+)
+
+// Clean trims a term and collapses inner whitespace, so "  Land   Rover "
+// becomes "Land Rover".
+func Clean(term string) string {
+	return strings.Join(strings.Fields(term), " ")
+}
+
+func key(term string) string {
+	return strings.ToLower(Clean(term))
+}
+
+// terms maps the case-insensitive key of a term to its canonical spelling.
+type terms map[string]string
+
+func (t terms) add(kind, name string) (string, error) {
+	name = Clean(name)
+	if name == "" {
+		return "", ErrorTermEmpty{kind}
+	}
+	if existing, exists := t[key(name)]; exists {
+		return "", ErrorTermExists{kind, existing}
+	}
+	t[key(name)] = name
+	return name, nil
+}
+
+func (t terms) lookup(kind, name string) (string, error) {
+	canonical, exists := t[key(name)]
+	if !exists {
+		return "", ErrorTermNotFound{kind, Clean(name)}
+	}
+	return canonical, nil
+}
+
+func (t terms) rename(kind, name, newName string) (string, error) {
+	if _, exists := t[key(name)]; !exists {
+		return "", ErrorTermNotFound{kind, Clean(name)}
+	}
+	newName = Clean(newName)
+	if newName == "" {
+		return "", ErrorTermEmpty{kind}
+	}
+	if existing, exists := t[key(newName)]; exists && key(newName) != key(name) {
+		return "", ErrorTermExists{kind, existing}
+	}
+	delete(t, key(name))
+	t[key(newName)] = newName
+	return newName, nil
+}
+
+func (t terms) remove(kind, name string) error {
+	if _, exists := t[key(name)]; !exists {
+		return ErrorTermNotFound{kind, Clean(name)}
+	}
+	delete(t, key(name))
+	return nil
+}
+
+func (t terms) list() []string {
+	list := make([]string, 0, len(t))
+	for _, name := range t {
+		list = append(list, name)
+	}
+	slices.Sort(list)
+	return list
+}
+
+type makeEntry struct {
+	name   string
+	models terms
+}
+
+/*
+Registry holds the reference data cars are checked against: the known
+makes, the models of each make and the allowed categories. A vocabulary
+is only enforced once it has entries, so an empty registry accepts any
+make, a make without models accepts any model and so on.
+*/
+type Registry struct {
+	makes      map[string]*makeEntry
+	categories terms
+	mu         *sync.RWMutex
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		makes:      make(map[string]*makeEntry),
+		categories: make(terms),
+		mu:         &sync.RWMutex{},
+	}
+}
+
+// seedFile is the format of a vocabulary file, models listed by make.
+type seedFile struct {
+	Makes      map[string][]string `json:"makes"`
+	Categories []string            `json:"categories"`
+}
+
+/*
+Load reads a vocabulary file such as:
+
+	{"makes": {"Toyota": ["Camry", "Corolla"], "Honda": []}, "categories": ["Sedan", "SUV"]}
+*/
+func Load(path string) (*Registry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var seed seedFile
+	if err := json.Unmarshal(b, &seed); err != nil {
+		return nil, err
+	}
+
+	r := NewRegistry()
+	for makeName, models := range seed.Makes {
+		if _, err := r.AddMake(makeName); err != nil {
+			return nil, err
+		}
+		for _, model := range models {
+			if _, err := r.AddModel(makeName, model); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, category := range seed.Categories {
+		if _, err := r.AddCategory(category); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *Registry) Makes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]string, 0, len(r.makes))
+	for _, entry := range r.makes {
+		list = append(list, entry.name)
+	}
+	slices.Sort(list)
+	return list
+}
+
+// AddMake registers a make and returns its cleaned name.
+func (r *Registry) AddMake(name string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name = Clean(name)
+	if name == "" {
+		return "", ErrorTermEmpty{KindMake}
+	}
+	if existing, exists := r.makes[key(name)]; exists {
+		return "", ErrorTermExists{KindMake, existing.name}
+	}
+	r.makes[key(name)] = &makeEntry{name: name, models: make(terms)}
+	return name, nil
+}
+
+// RenameMake changes the spelling of a make, keeping its models.
+func (r *Registry) RenameMake(name, newName string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, exists := r.makes[key(name)]
+	if !exists {
+		return "", ErrorTermNotFound{KindMake, Clean(name)}
+	}
+	newName = Clean(newName)
+	if newName == "" {
+		return "", ErrorTermEmpty{KindMake}
+	}
+	if other, exists := r.makes[key(newName)]; exists && other != entry {
+		return "", ErrorTermExists{KindMake, other.name}
+	}
+	delete(r.makes, key(name))
+	entry.name = newName
+	r.makes[key(newName)] = entry
+	return newName, nil
+}
+
+// DeleteMake removes a make and its models, cars already stored keep it.
+func (r *Registry) DeleteMake(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.makes[key(name)]; !exists {
+		return ErrorTermNotFound{KindMake, Clean(name)}
+	}
+	delete(r.makes, key(name))
+	return nil
+}
+
+func (r *Registry) Models(makeName string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, exists := r.makes[key(makeName)]
+	if !exists {
+		return nil, ErrorTermNotFound{KindMake, Clean(makeName)}
+	}
+	return entry.models.list(), nil
+}
+
+func (r *Registry) AddModel(makeName, model string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, exists := r.makes[key(makeName)]
+	if !exists {
+		return "", ErrorTermNotFound{KindMake, Clean(makeName)}
+	}
+	return entry.models.add(KindModel, model)
+}
+
+func (r *Registry) RenameModel(makeName, model, newModel string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, exists := r.makes[key(makeName)]
+	if !exists {
+		return "", ErrorTermNotFound{KindMake, Clean(makeName)}
+	}
+	return entry.models.rename(KindModel, model, newModel)
+}
+
+func (r *Registry) DeleteModel(makeName, model string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, exists := r.makes[key(makeName)]
+	if !exists {
+		return ErrorTermNotFound{KindMake, Clean(makeName)}
+	}
+	return entry.models.remove(KindModel, model)
+}
+
+func (r *Registry) Categories() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.categories.list()
+}
+
+func (r *Registry) AddCategory(name string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.categories.add(KindCategory, name)
+}
+
+func (r *Registry) RenameCategory(name, newName string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.categories.rename(KindCategory, name, newName)
+}
+
+func (r *Registry) DeleteCategory(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.categories.remove(KindCategory, name)
+}
+
+/*
+Normalize cleans the whitespace of the make, model and category of record
+and replaces them by their canonical spelling. Unknown terms are reported
+as invalid fields, empty ones are left for car.Record.Validate to report.
+A nil registry only cleans whitespace.
+*/
+func (r *Registry) Normalize(record car.Record) (car.Record, error) {
+	record.Make = Clean(record.Make)
+	record.Model = Clean(record.Model)
+	record.Category = Clean(record.Category)
+	if r == nil {
+		return record, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.makes) > 0 && record.Make != "" {
+		entry, exists := r.makes[key(record.Make)]
+		if !exists {
+			return car.Record{}, car.ErrorFieldInvalid{Field: "Make", Value: record.Make}
+		}
+		record.Make = entry.name
+
+		if len(entry.models) > 0 && record.Model != "" {
+			model, err := entry.models.lookup(KindModel, record.Model)
+			if err != nil {
+				return car.Record{}, car.ErrorFieldInvalid{Field: "Model", Value: record.Model}
+			}
+			record.Model = model
+		}
+	}
+
+	if len(r.categories) > 0 && record.Category != "" {
+		category, err := r.categories.lookup(KindCategory, record.Category)
+		if err != nil {
+			return car.Record{}, car.ErrorFieldInvalid{Field: "Category", Value: record.Category}
+		}
+		record.Category = category
+	}
+
+	return record, nil
+}
@@ -0,0 +1,157 @@
+package vocab
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/YoungOak/GoAPI/car"
+)
+
+func TestRegistry_Normalize(t *testing.T) {
+	registry := NewRegistry()
+	_, _ = registry.AddMake("Toyota")
+	_, _ = registry.AddModel("toyota", "Camry")
+	_, _ = registry.AddMake("Land  Rover")
+	_, _ = registry.AddCategory("Sedan")
+	_, _ = registry.AddCategory("SUV")
+
+	var record car.Record
+
+	tests := []struct {
+		name       string
+		make       string
+		model      string
+		category   string
+		wantRecord car.Record
+		wantErr    error
+	}{
+		{
+			name:       "canonical casing and whitespace",
+			make:       "TOYOTA ",
+			model:      " camry",
+			category:   "sedan",
+			wantRecord: car.Record{Make: "Toyota", Model: "Camry", Category: "Sedan"},
+		},
+		{
+			name:       "make without models accepts any model",
+			make:       "land rover",
+			model:      "Defender  90",
+			category:   "suv",
+			wantRecord: car.Record{Make: "Land Rover", Model: "Defender 90", Category: "SUV"},
+		},
+		{
+			name:     "unknown make",
+			make:     "Tesla",
+			model:    "Model 3",
+			category: "Sedan",
+			wantErr:  car.ErrorFieldInvalid{Field: "Make", Value: "Tesla"},
+		},
+		{
+			name:     "unknown model of make",
+			make:     "Toyota",
+			model:    "Civic",
+			category: "Sedan",
+			wantErr:  car.ErrorFieldInvalid{Field: "Model", Value: "Civic"},
+		},
+		{
+			name:     "unknown category",
+			make:     "Toyota",
+			model:    "Camry",
+			category: "Limousine",
+			wantErr:  car.ErrorFieldInvalid{Field: "Category", Value: "Limousine"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record.Make, record.Model, record.Category = tt.make, tt.model, tt.category
+			gotRecord, err := registry.Normalize(record)
+			if err != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("unexpected error, wanted success, got: %v", err)
+				}
+				if err.Error() != tt.wantErr.Error() {
+					t.Fatalf("unexpected error, wanted: %v, got: %v", tt.wantErr, err)
+				}
+				return
+			} else if tt.wantErr != nil {
+				t.Fatalf("unexpected success, expected error: %s", tt.wantErr.Error())
+			}
+			if !reflect.DeepEqual(gotRecord, tt.wantRecord) {
+				t.Fatalf("unexpected record, wanted: %+v, got: %+v", tt.wantRecord, gotRecord)
+			}
+		})
+	}
+
+	var nilRegistry *Registry
+	gotRecord, err := nilRegistry.Normalize(car.Record{Make: " Any  Make "})
+	if err != nil || gotRecord.Make != "Any Make" {
+		t.Fatalf("expected nil registry to only clean whitespace, got: %+v, error: %v", gotRecord, err)
+	}
+}
+
+func TestRegistry_CRUD(t *testing.T) {
+	registry := NewRegistry()
+
+	if _, err := registry.AddMake("Toyota"); err != nil {
+		t.Fatalf("unexpected error adding make: %v", err)
+	}
+	_, err := registry.AddMake(" TOYOTA")
+	wantErr := ErrorTermExists{KindMake, "Toyota"}
+	if err == nil || err.Error() != wantErr.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr, err)
+	}
+	if _, err := registry.AddMake("  "); err == nil {
+		t.Fatal("expected empty make to be rejected")
+	}
+
+	_, _ = registry.AddModel("Toyota", "Camry")
+	if name, err := registry.RenameMake("toyota", "TOYOTA Motors"); err != nil || name != "TOYOTA Motors" {
+		t.Fatalf("unexpected rename result: %s, error: %v", name, err)
+	}
+	models, err := registry.Models("toyota motors")
+	if err != nil || !reflect.DeepEqual(models, []string{"Camry"}) {
+		t.Fatalf("expected models to follow renamed make, got: %v, error: %v", models, err)
+	}
+
+	if err := registry.DeleteModel("Toyota Motors", "camry"); err != nil {
+		t.Fatalf("unexpected error deleting model: %v", err)
+	}
+	if err := registry.DeleteMake("Toyota Motors"); err != nil {
+		t.Fatalf("unexpected error deleting make: %v", err)
+	}
+	err = registry.DeleteMake("Toyota Motors")
+	wantErr2 := ErrorTermNotFound{KindMake, "Toyota Motors"}
+	if err == nil || err.Error() != wantErr2.Error() {
+		t.Fatalf("unexpected error, wanted: %v, got: %v", wantErr2, err)
+	}
+
+	_, _ = registry.AddCategory("suv")
+	if _, err := registry.RenameCategory("SUV", "SUV"); err != nil {
+		t.Fatalf("unexpected error fixing category casing: %v", err)
+	}
+	if got := registry.Categories(); !reflect.DeepEqual(got, []string{"SUV"}) {
+		t.Fatalf("unexpected categories, got: %v", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vocabulary.json")
+	content := `{"makes": {"Toyota": ["Camry", "Corolla"], "Honda": []}, "categories": ["Sedan"]}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error loading vocabulary: %v", err)
+	}
+	if got := registry.Makes(); !reflect.DeepEqual(got, []string{"Honda", "Toyota"}) {
+		t.Fatalf("unexpected makes, got: %v", got)
+	}
+	if got, _ := registry.Models("Toyota"); !reflect.DeepEqual(got, []string{"Camry", "Corolla"}) {
+		t.Fatalf("unexpected models, got: %v", got)
+	}
+}